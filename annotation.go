@@ -0,0 +1,55 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 The Ebitengine Authors
+
+package microui
+
+import "image"
+
+// annotation is an overlay drawn by f next to the control at rect.
+type annotation struct {
+	rect image.Rectangle
+	f    func(w WidgetContext, r image.Rectangle)
+}
+
+// Annotate draws an overlay anchored to the last control, like a validation
+// badge, a shortcut hint or a drag handle. f draws it around r, the area of
+// the control, in front of all the windows and without the clipping of the
+// control. The overlay follows the control when it is scrolled, and isn't
+// drawn when the control is scrolled out of view.
+func (c *Context) Annotate(f func(w WidgetContext, r image.Rectangle)) {
+	r := c.lastRect
+	if len(c.clipStack) > 0 && !r.Overlaps(c.clipRect()) {
+		return
+	}
+	if cnt := c.rootContainer(); cnt != nil {
+		if cnt.Offscreen || cnt.Viewport != 0 {
+			return
+		}
+		r = localTransform(cnt).rect(r)
+	}
+	c.annotations = append(c.annotations, annotation{rect: r, f: f})
+}
+
+// annotationWindow draws the annotations of the frame.
+func (c *Context) annotationWindow() {
+	if len(c.annotations) == 0 {
+		return
+	}
+	annotations := c.annotations
+	c.annotations = nil
+
+	// a window covering everything, in front of the other windows
+	cnt := c.Container("!annotations")
+	cnt.Rect = unclippedRect
+	cnt.Open = true
+	cnt.Layer = LayerOverlay
+	if cnt.ZIndex < c.lastZIndex {
+		c.bringToFront(cnt)
+	}
+	opt := OptNoFrame | OptNoResize | OptNoScroll | OptNoTitle | OptNoInteract | OptInputPassthrough
+	c.window("!annotations", unclippedRect, opt, func(res Response) {
+		for _, a := range annotations {
+			a.f(c.Widget(), a.rect)
+		}
+	})
+}
@@ -0,0 +1,263 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 The Ebitengine Authors
+
+package microui
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// bidi classes, a subset of the classes of the Unicode bidirectional
+// algorithm
+const (
+	bidiL = iota
+	bidiR
+	bidiEN
+	bidiON
+)
+
+// text directions
+const (
+	textDirAuto = iota
+	textDirLTR
+	textDirRTL
+)
+
+// bidiUnit is a grapheme cluster of a text with its resolved embedding level.
+type bidiUnit struct {
+	start, end int
+	level      int
+}
+
+func bidiClass(r rune) int {
+	switch {
+	case unicode.IsDigit(r):
+		return bidiEN
+	case unicode.IsLetter(r) && unicode.In(r, unicode.Hebrew, unicode.Arabic, unicode.Syriac, unicode.Thaana, unicode.Nko):
+		return bidiR
+	case unicode.IsLetter(r):
+		return bidiL
+	}
+	return bidiON
+}
+
+// hasRTL reports whether str contains right-to-left characters.
+func hasRTL(str string) bool {
+	for _, r := range str {
+		if bidiClass(r) == bidiR {
+			return true
+		}
+	}
+	return false
+}
+
+// bidiUnits returns the grapheme clusters of the single line str in visual
+// order, from left to right, with their embedding levels.
+//
+// The paragraph direction is dir, or the direction of the first strong
+// character if dir is textDirAuto.
+//
+// This is a simplified version of the Unicode bidirectional algorithm without
+// explicit embeddings, which is enough for right-to-left strings embedded in
+// left-to-right text and the other way around.
+func bidiUnits(str string, dir int) []bidiUnit {
+	var units []bidiUnit
+	var classes []int
+	for i := 0; i < len(str); {
+		j := nextGrapheme(str, i)
+		r, _ := utf8.DecodeRuneInString(str[i:j])
+		units = append(units, bidiUnit{start: i, end: j})
+		classes = append(classes, bidiClass(r))
+		i = j
+	}
+
+	// the paragraph direction is the direction of the first strong character
+	// unless it is forced
+	base := bidiL
+	switch dir {
+	case textDirAuto:
+		for _, cls := range classes {
+			if cls == bidiL || cls == bidiR {
+				base = cls
+				break
+			}
+		}
+	case textDirRTL:
+		base = bidiR
+	}
+
+	// numbers following left-to-right text are left-to-right
+	strong := base
+	for i, cls := range classes {
+		switch cls {
+		case bidiL, bidiR:
+			strong = cls
+		case bidiEN:
+			if strong == bidiL {
+				classes[i] = bidiL
+			}
+		}
+	}
+
+	// neutrals take the direction of the surrounding text if it is the same on
+	// both sides, and the paragraph direction otherwise
+	strongDir := func(cls int) int {
+		if cls == bidiEN {
+			return bidiR
+		}
+		return cls
+	}
+	for i := 0; i < len(classes); {
+		if classes[i] != bidiON {
+			i++
+			continue
+		}
+		j := i
+		for j < len(classes) && classes[j] == bidiON {
+			j++
+		}
+		before, after := base, base
+		if i > 0 {
+			before = strongDir(classes[i-1])
+		}
+		if j < len(classes) {
+			after = strongDir(classes[j])
+		}
+		d := base
+		if before == after {
+			d = before
+		}
+		for k := i; k < j; k++ {
+			classes[k] = d
+		}
+		i = j
+	}
+
+	// resolve the levels
+	for i, cls := range classes {
+		switch {
+		case base == bidiL && cls == bidiR:
+			units[i].level = 1
+		case base == bidiL && cls == bidiEN:
+			units[i].level = 2
+		case base == bidiR && cls == bidiR:
+			units[i].level = 1
+		case base == bidiR:
+			units[i].level = 2
+		}
+	}
+	// trailing whitespaces take the paragraph level
+	for i := len(units) - 1; i >= 0; i-- {
+		if strings.TrimSpace(str[units[i].start:units[i].end]) != "" {
+			break
+		}
+		units[i].level = base
+	}
+
+	// reverse the runs from the highest level to the lowest odd level
+	var highest int
+	for _, u := range units {
+		highest = max(highest, u.level)
+	}
+	for level := highest; level >= 1; level-- {
+		for i := 0; i < len(units); {
+			if units[i].level < level {
+				i++
+				continue
+			}
+			j := i
+			for j < len(units) && units[j].level >= level {
+				j++
+			}
+			for a, b := i, j-1; a < b; a, b = a+1, b-1 {
+				units[a], units[b] = units[b], units[a]
+			}
+			i = j
+		}
+	}
+	return units
+}
+
+var bidiMirrors = map[rune]rune{
+	'(': ')', ')': '(',
+	'[': ']', ']': '[',
+	'{': '}', '}': '{',
+	'<': '>', '>': '<',
+	'«': '»', '»': '«',
+}
+
+// unitText returns the text of u in str, mirrored if it is right-to-left.
+func unitText(str string, u bidiUnit) string {
+	s := str[u.start:u.end]
+	if u.level%2 == 1 {
+		r, n := utf8.DecodeRuneInString(s)
+		if m, ok := bidiMirrors[r]; ok {
+			return string(m) + s[n:]
+		}
+	}
+	return s
+}
+
+// visualText returns str in visual order, with right-to-left runs reversed,
+// for the paragraph direction dir.
+func visualText(str string, dir int) string {
+	if dir != textDirRTL && !hasRTL(str) {
+		return str
+	}
+	lines := strings.Split(str, "\n")
+	for i, l := range lines {
+		var b strings.Builder
+		for _, u := range bidiUnits(l, dir) {
+			b.WriteString(unitText(l, u))
+		}
+		lines[i] = b.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// bidiIndexAt is like textIndexAt for a line with right-to-left runs.
+func bidiIndexAt(str string, x int, dir int) int {
+	units := bidiUnits(str, dir)
+	var w int
+	for _, u := range units {
+		uw := textWidth(unitText(str, u))
+		if x < w+uw {
+			left := x < w+uw/2
+			if left == (u.level%2 == 0) {
+				return u.start
+			}
+			return u.end
+		}
+		w += uw
+	}
+	if len(units) == 0 {
+		return 0
+	}
+	if u := units[len(units)-1]; u.level%2 == 1 {
+		return u.start
+	}
+	return units[len(units)-1].end
+}
+
+// caretX returns the x offset of the caret at the byte offset pos of the
+// single line str drawn with the paragraph direction dir.
+func caretX(str string, pos int, dir int) int {
+	if dir != textDirRTL && !hasRTL(str) {
+		return textWidth(str[:pos])
+	}
+	units := bidiUnits(str, dir)
+	var x int
+	for _, u := range units {
+		w := textWidth(unitText(str, u))
+		switch {
+		case u.start == pos && u.level%2 == 0, u.end == pos && pos == len(str) && u.level%2 == 1:
+			return x
+		case u.start == pos, u.end == pos && pos == len(str):
+			return x + w
+		}
+		x += w
+	}
+	return x
+}
@@ -7,7 +7,10 @@ import (
 	"bytes"
 	"embed"
 	"image"
+	"image/color"
+	"slices"
 	"sync"
+	"time"
 
 	"github.com/hajimehoshi/bitmapfont/v3"
 	"github.com/hajimehoshi/ebiten/v2"
@@ -16,7 +19,13 @@ import (
 	"github.com/hajimehoshi/ebiten/v2/vector"
 )
 
-var fontFace = text.NewGoXFace(bitmapfont.Face)
+var (
+	defaultFontFace text.Face = text.NewGoXFace(bitmapfont.Face)
+	fontFace                  = defaultFontFace
+
+	// atlas caches the glyphs of a custom font face, if any.
+	atlas *glyphAtlas
+)
 
 func DrawText(dst *ebiten.Image, str string, op *text.DrawOptions) {
 	text.Draw(dst, str, fontFace, op)
@@ -70,71 +79,246 @@ func iconImage(icon icon) *ebiten.Image {
 	return iconMap[icon]
 }
 
+// mouseButtons are the mouse buttons handled by the context.
+var mouseButtons = [...]ebiten.MouseButton{ebiten.MouseButtonLeft, ebiten.MouseButtonRight, ebiten.MouseButtonMiddle}
+
 func (c *Context) updateInput() {
-	cx, cy := ebiten.CursorPosition()
+	// TODO: Use exp/textinput.Field.
+	s := c.inputState
+	c.inputState = nil
+	var cursor image.Point
+	var wx, wy float64
+	var runes []rune
+	if s != nil {
+		cursor, wx, wy, runes = s.Cursor, s.WheelX, s.WheelY, s.Runes
+	} else {
+		cursor = image.Pt(ebiten.CursorPosition())
+		wx, wy = ebiten.Wheel()
+		c.inputRunes = ebiten.AppendInputChars(c.inputRunes[:0])
+		runes = c.inputRunes
+	}
+
+	c.lastScreenCursor = c.screenCursor
+	c.screenCursor = cursor
+	cx, cy := c.transform.invert(c.screenCursor.X, c.screenCursor.Y)
 	c.inputMouseMove(cx, cy)
-	if wx, wy := ebiten.Wheel(); wx != 0 || wy != 0 {
-		c.inputScroll(int(wx*-30), int(wy*-30))
+	if wx != 0 || wy != 0 {
+		c.inputScroll(c.wheelDistance(wx), c.wheelDistance(wy))
 	}
-	// TODO: Use exp/textinput.Field.
-	chars := ebiten.AppendInputChars(nil)
-	if len(chars) > 0 {
-		c.inputText(chars)
-	}
-	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
-		c.inputMouseDown(cx, cy, ebiten.MouseButtonLeft)
-	} else if inpututil.IsMouseButtonJustReleased(ebiten.MouseButtonLeft) {
-		c.inputMouseUp(cx, cy, ebiten.MouseButtonLeft)
-	}
-	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonRight) {
-		c.inputMouseDown(cx, cy, ebiten.MouseButtonRight)
-	} else if inpututil.IsMouseButtonJustReleased(ebiten.MouseButtonRight) {
-		c.inputMouseUp(cx, cy, ebiten.MouseButtonRight)
-	}
-	for _, k := range []ebiten.Key{ebiten.KeyAlt, ebiten.KeyBackspace, ebiten.KeyControl, ebiten.KeyEnter, ebiten.KeyShift} {
-		if inpututil.IsKeyJustPressed(k) {
+	if len(runes) > 0 {
+		c.inputText(runes)
+	}
+	// the presses include the repeats of the held keys
+	c.keysPressed, c.keysReleased = c.keysPressed[:0], c.keysReleased[:0]
+	if s != nil {
+		c.applyInputState(cx, cy, s)
+	} else {
+		c.applyEbitenInput(cx, cy)
+	}
+	c.applyVirtualKeys()
+}
+
+// applyEbitenInput applies the presses and the releases of the buttons and
+// the keys read from Ebitengine.
+func (c *Context) applyEbitenInput(cx, cy int) {
+	for _, btn := range mouseButtons {
+		if inpututil.IsMouseButtonJustPressed(btn) {
+			c.inputMouseDown(cx, cy, btn)
+		} else if inpututil.IsMouseButtonJustReleased(btn) {
+			c.inputMouseUp(cx, cy, btn)
+		}
+	}
+	c.keysHeld = inpututil.AppendPressedKeys(c.keysHeld[:0])
+	for _, k := range c.keysHeld {
+		pressed := inpututil.IsKeyJustPressed(k)
+		if repeated := c.isKeyRepeated(k, pressed); pressed || repeated {
+			c.keysPressed = append(c.keysPressed, k)
 			c.inputKeyDown(k)
-		} else if inpututil.IsKeyJustReleased(k) {
+		}
+	}
+	c.keysReleased = inpututil.AppendJustReleasedKeys(c.keysReleased)
+	for _, k := range c.keysReleased {
+		delete(c.keyRepeatAt, k)
+		c.inputKeyUp(k)
+	}
+}
+
+// applyInputState applies the presses and the releases of the buttons and
+// the keys of s, relative to the buttons and the keys held in the previous
+// frame.
+func (c *Context) applyInputState(cx, cy int, s *InputState) {
+	for _, btn := range mouseButtons {
+		held := slices.Contains(s.MouseButtons, btn)
+		was := (c.mouseDown & mouseButtonToInt(btn)) != 0
+		if held && !was {
+			c.inputMouseDown(cx, cy, btn)
+		} else if !held && was {
+			c.inputMouseUp(cx, cy, btn)
+		}
+	}
+	for _, k := range s.Keys {
+		was := slices.Contains(c.keysHeld, k)
+		if repeated := c.isKeyRepeated(k, !was); !was || repeated {
+			c.keysPressed = append(c.keysPressed, k)
+			c.inputKeyDown(k)
+		}
+	}
+	for _, k := range c.keysHeld {
+		if !slices.Contains(s.Keys, k) {
+			c.keysReleased = append(c.keysReleased, k)
+			delete(c.keyRepeatAt, k)
 			c.inputKeyUp(k)
 		}
 	}
+	c.keysHeld = append(c.keysHeld[:0], s.Keys...)
+}
+
+// isRepeatKey reports whether key is repeated while it is held, like the keys
+// editing text and moving the caret or the focus. The modifiers and the keys
+// confirming an action, like Enter, aren't repeated.
+func isRepeatKey(key ebiten.Key) bool {
+	switch key {
+	case ebiten.KeyBackspace, ebiten.KeyDelete, ebiten.KeyTab,
+		ebiten.KeyArrowLeft, ebiten.KeyArrowRight, ebiten.KeyArrowUp, ebiten.KeyArrowDown,
+		ebiten.KeyHome, ebiten.KeyEnd, ebiten.KeyPageUp, ebiten.KeyPageDown:
+		return true
+	}
+	return false
+}
+
+// isKeyRepeated reports whether a held key should be repeated this frame,
+// after the key repeat delay and then at every key repeat interval, if it is
+// a repeated key. pressed reports whether the key is pressed this frame.
+func (c *Context) isKeyRepeated(key ebiten.Key, pressed bool) bool {
+	if !isRepeatKey(key) {
+		return false
+	}
+	if c.keyRepeatAt == nil {
+		c.keyRepeatAt = map[ebiten.Key]time.Duration{}
+	}
+	if pressed {
+		c.keyRepeatAt[key] = c.now + c.Style.KeyRepeatDelay
+		return false
+	}
+	at, ok := c.keyRepeatAt[key]
+	if !ok || c.now < at {
+		return false
+	}
+	c.keyRepeatAt[key] = max64(at+c.Style.KeyRepeatInterval, c.now)
+	return true
+}
+
+// defaultDeltaTime returns the duration of a tick of the game.
+func defaultDeltaTime() time.Duration {
+	tps := ebiten.TPS()
+	if tps <= 0 {
+		// the ticks are synced with the frames
+		tps = int(ebiten.ActualFPS())
+	}
+	if tps <= 0 {
+		tps = ebiten.DefaultTPS
+	}
+	return time.Second / time.Duration(tps)
+}
+
+// colorScale returns the color scale for the premultiplied color clr.
+func colorScale(clr color.RGBA64) ebiten.ColorScale {
+	var cs ebiten.ColorScale
+	cs.Scale(float32(clr.R)/0xffff, float32(clr.G)/0xffff, float32(clr.B)/0xffff, float32(clr.A)/0xffff)
+	return cs
 }
 
 func (c *Context) Draw(screen *ebiten.Image) {
-	target := screen
+	// keep the area of the screen in the UI space to place the popups
+	b := screen.Bounds()
+	c.screenRect = image.Rectangle{
+		Min: image.Pt(c.transform.invert(b.Min.X, b.Min.Y)),
+		Max: image.Pt(c.transform.invert(b.Max.X, b.Max.Y)),
+	}
+	r := renderer{screen: screen, target: screen, base: c.transform, t: c.transform}
 	var cmd *command
 	for c.nextCommand(&cmd) {
-		switch cmd.typ {
-		case commandRect:
-			vector.DrawFilledRect(
-				target,
-				float32(cmd.rect.rect.Min.X),
-				float32(cmd.rect.rect.Min.Y),
-				float32(cmd.rect.rect.Dx()),
-				float32(cmd.rect.rect.Dy()),
-				cmd.rect.color,
-				false,
-			)
-		case commandText:
-			op := &text.DrawOptions{}
-			op.GeoM.Translate(float64(cmd.text.pos.X), float64(cmd.text.pos.Y))
-			op.ColorScale.ScaleWithColor(cmd.text.color)
-			text.Draw(target, cmd.text.str, fontFace, op)
-		case commandIcon:
-			img := iconImage(cmd.icon.icon)
-			if img == nil {
-				continue
-			}
-			op := &ebiten.DrawImageOptions{}
-			x := cmd.icon.rect.Min.X + (cmd.icon.rect.Dx()-img.Bounds().Dx())/2
-			y := cmd.icon.rect.Min.Y + (cmd.icon.rect.Dy()-img.Bounds().Dy())/2
-			op.GeoM.Translate(float64(x), float64(y))
-			op.ColorScale.ScaleWithColor(cmd.icon.color)
-			target.DrawImage(img, op)
-		case commandDraw:
-			cmd.draw.f(target)
-		case commandClip:
-			target = screen.SubImage(cmd.clip.rect).(*ebiten.Image)
+		r.draw(cmd)
+	}
+	r.flush()
+}
+
+// renderer draws commands to a screen.
+type renderer struct {
+	screen *ebiten.Image
+	target *ebiten.Image
+	// base is the transform of the UI space, and t is the transform of the
+	// current commands.
+	base transform
+	t    transform
+}
+
+func (r *renderer) draw(cmd *command) {
+	target, t := r.target, r.t
+	// draw the batched text before anything else is drawn over it
+	if atlas != nil && cmd.typ != commandText {
+		atlas.flush(target)
+	}
+	switch cmd.typ {
+	case commandRect:
+		x0, y0 := t.apply(float64(cmd.rect.rect.Min.X), float64(cmd.rect.rect.Min.Y))
+		x1, y1 := t.apply(float64(cmd.rect.rect.Max.X), float64(cmd.rect.rect.Max.Y))
+		vector.DrawFilledRect(
+			target,
+			float32(x0),
+			float32(y0),
+			float32(x1-x0),
+			float32(y1-y0),
+			cmd.rect.color,
+			false,
+		)
+	case commandText:
+		if atlas != nil {
+			atlas.appendText(target, cmd.text.str, cmd.text.pos, cmd.text.color, t)
+			return
 		}
+		op := &text.DrawOptions{}
+		op.GeoM.Translate(float64(cmd.text.pos.X), float64(cmd.text.pos.Y))
+		op.GeoM = t.geoM(op.GeoM)
+		op.ColorScale = colorScale(cmd.text.color)
+		text.Draw(target, cmd.text.str, fontFace, op)
+	case commandIcon:
+		img := iconImage(cmd.icon.icon)
+		if img == nil {
+			return
+		}
+		op := &ebiten.DrawImageOptions{}
+		x := cmd.icon.rect.Min.X + (cmd.icon.rect.Dx()-img.Bounds().Dx())/2
+		y := cmd.icon.rect.Min.Y + (cmd.icon.rect.Dy()-img.Bounds().Dy())/2
+		op.GeoM.Translate(float64(x), float64(y))
+		op.GeoM = t.geoM(op.GeoM)
+		op.ColorScale = colorScale(cmd.icon.color)
+		target.DrawImage(img, op)
+	case commandDraw:
+		cmd.draw.f(target)
+	case commandPolyline:
+		w := float32(cmd.polyline.width * t.scaleOrOne())
+		pts := cmd.polyline.points
+		for i := 1; i < len(pts); i++ {
+			x0, y0 := t.apply(float64(pts[i-1].X), float64(pts[i-1].Y))
+			x1, y1 := t.apply(float64(pts[i].X), float64(pts[i].Y))
+			vector.StrokeLine(target, float32(x0), float32(y0), float32(x1), float32(y1), w, cmd.polyline.color, true)
+		}
+	case commandClip:
+		// the unclipped area is the whole screen whatever the transform
+		if cmd.clip.rect == unclippedRect {
+			r.target = r.screen
+			return
+		}
+		r.target = r.screen.SubImage(t.rect(cmd.clip.rect)).(*ebiten.Image)
+	case commandTransform:
+		r.t = cmd.transform.transform.then(r.base)
+	}
+}
+
+// flush draws the batched text.
+func (r *renderer) flush() {
+	if atlas != nil {
+		atlas.flush(r.target)
 	}
 }
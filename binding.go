@@ -0,0 +1,72 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 The Ebitengine Authors
+
+package microui
+
+// Binding gives access to a value living behind accessors, like a field of
+// an ECS component or a networked state.
+type Binding[T any] struct {
+	Get func() T
+	Set func(value T)
+}
+
+// PtrBinding returns a binding to the value pointed by ptr.
+func PtrBinding[T any](ptr *T) Binding[T] {
+	return Binding[T]{
+		Get: func() T { return *ptr },
+		Set: func(value T) { *ptr = value },
+	}
+}
+
+// valid reports whether both accessors of b are set.
+func (b Binding[T]) valid() bool {
+	return b.Get != nil && b.Set != nil
+}
+
+// bind calls f with a copy of the value of b, and sets the value back if f
+// reports a change.
+func bind[T comparable](b Binding[T], f func(value *T) Response) Response {
+	v := b.Get()
+	last := v
+	res := f(&v)
+	if v != last {
+		b.Set(v)
+	}
+	return res
+}
+
+// SliderBinding is like SliderEx for a bound value. As the value has no
+// address, the slider is identified by name.
+func (c *Context) SliderBinding(name string, b Binding[float64], low, high, step float64, format string, opt Option) Response {
+	id := c.id([]byte(name))
+	return bind(b, func(value *float64) Response {
+		return c.slider(value, id, low, high, step, nil, nil, valueFormat{verb: format}, opt)
+	})
+}
+
+// NumberBinding is like NumberEx for a bound value. As the value has no
+// address, the number is identified by name.
+func (c *Context) NumberBinding(name string, b Binding[float64], step float64, format string, opt Option) Response {
+	id := c.id([]byte(name))
+	return bind(b, func(value *float64) Response {
+		return c.number(value, id, step, valueFormat{verb: format}, opt)
+	})
+}
+
+// CheckboxBinding is like Checkbox for a bound state. As the state has no
+// address, the checkbox is identified by its label.
+func (c *Context) CheckboxBinding(label string, b Binding[bool]) Response {
+	id := c.id([]byte(label))
+	return bind(b, func(state *bool) Response {
+		return c.checkbox(label, id, state)
+	})
+}
+
+// TextBoxBinding is like TextBoxEx for a bound text. As the text has no
+// address, the text box is identified by name.
+func (c *Context) TextBoxBinding(name string, b Binding[string], opt Option) Response {
+	id := c.id([]byte(name))
+	return bind(b, func(buf *string) Response {
+		return c.textBoxRaw(buf, id, textBoxConfig{}, opt)
+	})
+}
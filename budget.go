@@ -0,0 +1,106 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 The Ebitengine Authors
+
+package microui
+
+import (
+	"slices"
+	"sort"
+)
+
+// BudgetReport is the breakdown of a frame exceeding the budget set with
+// SetFrameBudget.
+type BudgetReport struct {
+	// Commands and Controls are the numbers of draw commands and controls
+	// of the frame.
+	Commands int
+	Controls int
+
+	// Windows are the costs of the windows, from the most to the least
+	// draw commands.
+	Windows []WindowCost
+}
+
+// WindowCost is the number of draw commands and controls of a window in a
+// frame, not counting the windows built inside it.
+type WindowCost struct {
+	Name     string
+	Commands int
+	Controls int
+}
+
+type frameBudget struct {
+	maxCommands int
+	maxControls int
+	f           func(r BudgetReport)
+
+	controls map[*Container]int
+	total    int
+}
+
+// SetFrameBudget sets soft limits to the numbers of draw commands and of
+// controls of a frame, to catch the UI code building far more than intended,
+// like in a runaway loop. At the end of a frame exceeding a limit, f is called
+// with the breakdown of the frame by window. A limit of 0 is no limit, and a
+// nil f removes the budget.
+func (c *Context) SetFrameBudget(maxCommands, maxControls int, f func(r BudgetReport)) {
+	if f == nil {
+		c.budget = nil
+		return
+	}
+	c.budget = &frameBudget{
+		maxCommands: maxCommands,
+		maxControls: maxControls,
+		f:           f,
+		controls:    map[*Container]int{},
+	}
+}
+
+// countControl counts a control in the budget of the frame.
+func (c *Context) countControl() {
+	c.budget.controls[c.rootContainer()]++
+	c.budget.total++
+}
+
+// checkBudget calls the function of the budget if the frame exceeds it.
+func (c *Context) checkBudget() {
+	b := c.budget
+	if b == nil {
+		return
+	}
+	defer func() {
+		clear(b.controls)
+		b.total = 0
+	}()
+	if (b.maxCommands <= 0 || len(c.commandList) <= b.maxCommands) && (b.maxControls <= 0 || b.total <= b.maxControls) {
+		return
+	}
+
+	// the commands of a window are between its head and tail jumps, with
+	// the ones of the windows built inside it
+	roots := slices.Clone(c.rootList)
+	sort.Slice(roots, func(i, j int) bool {
+		return roots[i].HeadIdx < roots[j].HeadIdx
+	})
+	costs := make([]WindowCost, len(roots))
+	var stack []int
+	for i, cnt := range roots {
+		n := cnt.TailIdx - cnt.HeadIdx + 1
+		costs[i] = WindowCost{Name: cnt.name, Commands: n, Controls: b.controls[cnt]}
+		for len(stack) > 0 && roots[stack[len(stack)-1]].TailIdx < cnt.HeadIdx {
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) > 0 {
+			costs[stack[len(stack)-1]].Commands -= n
+		}
+		stack = append(stack, i)
+	}
+	sort.SliceStable(costs, func(i, j int) bool {
+		return costs[i].Commands > costs[j].Commands
+	})
+	b.f(BudgetReport{
+		Commands: len(c.commandList),
+		Controls: b.total,
+		Windows:  costs,
+	})
+}
@@ -0,0 +1,129 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 The Ebitengine Authors
+
+package microui
+
+import (
+	"image"
+	"math"
+	"unsafe"
+)
+
+// CanvasState is the view of a canvas built with Canvas.
+type CanvasState struct {
+	// X and Y are the position in the canvas at the top-left corner of the
+	// view.
+	X, Y float64
+	// Zoom is the scale of the canvas in the view. Zero means 1.
+	Zoom float64
+}
+
+// transform returns the transform of the canvas space to the area r.
+func (s *CanvasState) transform(r image.Rectangle) transform {
+	z := s.Zoom
+	if z == 0 {
+		z = 1
+	}
+	return transform{scale: z, x: float64(r.Min.X) - s.X*z, y: float64(r.Min.Y) - s.Y*z}
+}
+
+// Canvas is a panel showing an infinite space built with f, like the nodes of
+// a graph editor. The mouse wheel zooms the view, and dragging with the
+// middle mouse button pans it. The controls and the positions used in f,
+// like the mouse position seen by the controls, are in the canvas space.
+//
+// Canvases can't be nested.
+func (c *Context) Canvas(name string, s *CanvasState, f func()) {
+	id := c.pushID([]byte(name))
+	defer c.popID()
+	cnt := c.container(id, 0)
+	r := c.layoutNext()
+	cnt.Rect = r
+
+	// handle the view
+	panID := c.id([]byte("!pan"))
+	c.updateControl(panID, r, OptHoldFocus)
+	t := s.transform(r)
+	if c.mouseOver(r) && c.scrollDelta.Y != 0 {
+		mx, my := t.invert(c.mousePos.X, c.mousePos.Y)
+		z := t.scaleOrOne() * math.Pow(1.1, -float64(c.scrollDelta.Y)/30)
+		s.Zoom = clampF(z, 0.05, 20)
+		s.X = float64(mx) - float64(c.mousePos.X-r.Min.X)/s.Zoom
+		s.Y = float64(my) - float64(c.mousePos.Y-r.Min.Y)/s.Zoom
+		// consume the wheel so that it doesn't scroll the container
+		c.scrollDelta = image.Point{}
+	}
+	if c.focus == panID && (c.mouseDown&mouseMiddle) != 0 {
+		s.X -= float64(c.mouseDelta.X) / t.scaleOrOne()
+		s.Y -= float64(c.mouseDelta.Y) / t.scaleOrOne()
+	}
+	t = s.transform(r)
+	c.drawFrame(r, ColorBase)
+
+	// the following commands are in the canvas space, within the transform of
+	// the window, including its transition
+	prev := c.cmdTransform
+	c.pushTransform(t.then(prev))
+	defer c.pushTransform(prev)
+
+	body := t.invertRect(r.Intersect(c.clipRect()))
+	c.clipStack = append(c.clipStack, body)
+	defer c.popClipRect()
+
+	c.containerStack = append(c.containerStack, cnt)
+	cnt.Body = body
+	c.pushLayout(image.Rectangle{Max: body.Size()}, image.Point{})
+	defer c.popContainer()
+
+	mousePos, mouseDelta := c.mousePos, c.mouseDelta
+	defer func() {
+		c.mousePos, c.mouseDelta = mousePos, mouseDelta
+	}()
+	c.mousePos = image.Pt(t.invert(mousePos.X, mousePos.Y))
+	c.mouseDelta = c.mousePos.Sub(image.Pt(t.invert(mousePos.X-mouseDelta.X, mousePos.Y-mouseDelta.Y)))
+
+	f()
+}
+
+// CanvasNode is a node of a graph at rect in a canvas, with a title bar to
+// move it and a body built with f. It reports ResponseChange when the node is
+// moved.
+func (c *Context) CanvasNode(title string, rect *image.Rectangle, f func()) Response {
+	c.pushID(ptrToBytes(unsafe.Pointer(rect)))
+	defer c.popID()
+
+	var res Response
+	c.drawFrame(*rect, ColorWindowBG)
+	tr := *rect
+	tr.Max.Y = tr.Min.Y + c.Style.TitleHeight
+	c.drawFrame(tr, ColorTitleBG)
+	c.drawControlText(title, tr, ColorTitleText, 0)
+	id := c.id([]byte("!title"))
+	c.updateControl(id, tr, 0)
+	if c.focus == id && c.mouseDown == mouseLeft && c.mouseDelta != (image.Point{}) {
+		*rect = rect.Add(c.mouseDelta)
+		res |= ResponseChange
+	}
+
+	body := *rect
+	body.Min.Y = tr.Max.Y
+	c.pushLayout(body.Inset(c.Style.Padding), image.Point{})
+	defer func() {
+		c.layoutStack = c.layoutStack[:len(c.layoutStack)-1]
+	}()
+	c.pushClipRect(body)
+	defer c.popClipRect()
+	f()
+	return res
+}
+
+// HitTest returns the index of the last of rects under the mouse, like the
+// node of a graph drawn on top of the others, or -1 if there is none.
+func (c *Context) HitTest(rects []image.Rectangle) int {
+	for i := len(rects) - 1; i >= 0; i-- {
+		if c.mouseOver(rects[i]) {
+			return i
+		}
+	}
+	return -1
+}
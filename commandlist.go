@@ -6,41 +6,40 @@ package microui
 import (
 	"image"
 	"image/color"
+	"math"
 
 	"github.com/hajimehoshi/ebiten/v2"
 )
 
-// pushCommand adds a new command with type cmd_type to command_list
+// pushCommand adds a new command with type cmd_type to command_list.
+//
+// The commands are stored by value in a buffer reused across frames, so the
+// returned command is only valid until the next command is pushed.
 func (c *Context) pushCommand(cmd_type int) *command {
-	cmd := command{
-		typ: cmd_type,
-	}
 	//expect(uintptr(len(ctx.CommandList))*size+size < MU_COMMANDLIST_SIZE)
-	cmd.base.typ = cmd_type
-	cmd.idx = len(c.commandList)
-	c.commandList = append(c.commandList, &cmd)
-	return &cmd
+	c.commandList = append(c.commandList, command{
+		typ:  cmd_type,
+		idx:  len(c.commandList),
+		base: baseCommand{typ: cmd_type},
+	})
+	return &c.commandList[len(c.commandList)-1]
 }
 
 func (c *Context) nextCommand(cmd **command) bool {
 	if len(c.commandList) == 0 {
 		return false
 	}
-	if *cmd == nil {
-		*cmd = c.commandList[0]
-	} else {
-		*cmd = c.commandList[(*cmd).idx+1]
+	idx := 0
+	if *cmd != nil {
+		idx = (*cmd).idx + 1
 	}
 
-	for (*cmd).idx < len(c.commandList) {
+	for idx < len(c.commandList) {
+		*cmd = &c.commandList[idx]
 		if (*cmd).typ != commandJump {
 			return true
 		}
-		idx := (*cmd).jump.dstIdx
-		if idx > len(c.commandList)-1 {
-			break
-		}
-		*cmd = c.commandList[idx]
+		idx = (*cmd).jump.dstIdx
 	}
 	return false
 }
@@ -57,12 +56,27 @@ func (c *Context) setClip(rect image.Rectangle) {
 	cmd.clip.rect = rect
 }
 
+// faded returns clr faded by the current fade factor.
+func (c *Context) faded(clr color.Color) color.RGBA64 {
+	r, g, b, a := clr.RGBA()
+	if c.fade <= 0 {
+		return color.RGBA64{R: uint16(r), G: uint16(g), B: uint16(b), A: uint16(a)}
+	}
+	s := 1 - c.fade
+	return color.RGBA64{
+		R: uint16(float64(r) * s),
+		G: uint16(float64(g) * s),
+		B: uint16(float64(b) * s),
+		A: uint16(float64(a) * s),
+	}
+}
+
 func (c *Context) drawRect(rect image.Rectangle, color color.Color) {
 	rect2 := rect.Intersect(c.clipRect())
 	if rect2.Dx() > 0 && rect2.Dy() > 0 {
 		cmd := c.pushCommand(commandRect)
 		cmd.rect.rect = rect2
-		cmd.rect.color = color
+		cmd.rect.color = c.faded(color)
 	}
 }
 
@@ -74,6 +88,15 @@ func (c *Context) drawBox(rect image.Rectangle, color color.Color) {
 }
 
 func (c *Context) drawText(str string, pos image.Point, color color.Color) {
+	dir := textDirAuto
+	if c.Style.RightToLeft {
+		dir = textDirRTL
+	}
+	c.drawTextDir(str, pos, color, dir)
+}
+
+// drawTextDir is like drawText with the paragraph direction dir.
+func (c *Context) drawTextDir(str string, pos image.Point, color color.Color, dir int) {
 	rect := image.Rect(pos.X, pos.Y, pos.X+textWidth(str), pos.Y+lineHeight())
 	clipped := c.checkClip(rect)
 	if clipped == clipAll {
@@ -84,9 +107,9 @@ func (c *Context) drawText(str string, pos image.Point, color color.Color) {
 	}
 	// add command
 	cmd := c.pushCommand(commandText)
-	cmd.text.str = str
+	cmd.text.str = visualText(str, dir)
 	cmd.text.pos = pos
-	cmd.text.color = color
+	cmd.text.color = c.faded(color)
 	// reset clipping if it was set
 	if clipped != 0 {
 		c.setClip(unclippedRect)
@@ -106,13 +129,56 @@ func (c *Context) drawIcon(icon icon, rect image.Rectangle, color color.Color) {
 	cmd := c.pushCommand(commandIcon)
 	cmd.icon.icon = icon
 	cmd.icon.rect = rect
-	cmd.icon.color = color
+	cmd.icon.color = c.faded(color)
 	// reset clipping if it was set
 	if clipped != 0 {
 		c.setClip(unclippedRect)
 	}
 }
 
+// DrawPolyline draws the lines joining points with clr, width pixels wide.
+func (c *Context) DrawPolyline(points []image.Point, clr color.Color, width float64) {
+	if len(points) < 2 {
+		return
+	}
+	var rect image.Rectangle
+	for _, p := range points {
+		rect = rect.Union(image.Rectangle{Min: p, Max: p.Add(image.Pt(1, 1))})
+	}
+	w := int(width + 1)
+	clipped := c.checkClip(rect.Inset(-w))
+	if clipped == clipAll {
+		return
+	}
+	if clipped == clipPart {
+		c.setClip(c.clipRect())
+	}
+	cmd := c.pushCommand(commandPolyline)
+	// the points are copied as the command buffer is reused across frames
+	cmd.polyline.points = append([]image.Point(nil), points...)
+	cmd.polyline.color = c.faded(clr)
+	cmd.polyline.width = width
+	if clipped != 0 {
+		c.setClip(unclippedRect)
+	}
+}
+
+// DrawBezier draws the cubic Bézier curve from p0 to p3 with the control
+// points p1 and p2, like a link between two nodes of a graph.
+func (c *Context) DrawBezier(p0, p1, p2, p3 image.Point, clr color.Color, width float64) {
+	const n = 24
+	points := make([]image.Point, 0, n+1)
+	for i := 0; i <= n; i++ {
+		t := float64(i) / n
+		u := 1 - t
+		a, b, d, e := u*u*u, 3*u*u*t, 3*u*t*t, t*t*t
+		x := a*float64(p0.X) + b*float64(p1.X) + d*float64(p2.X) + e*float64(p3.X)
+		y := a*float64(p0.Y) + b*float64(p1.Y) + d*float64(p2.Y) + e*float64(p3.Y)
+		points = append(points, image.Pt(int(math.Round(x)), int(math.Round(y))))
+	}
+	c.DrawPolyline(points, clr, width)
+}
+
 func (c *Context) DrawControl(f func(screen *ebiten.Image)) {
 	c.setClip(c.clipRect())
 	defer c.setClip(unclippedRect)
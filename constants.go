@@ -6,6 +6,7 @@ package microui
 import (
 	"image"
 	"image/color"
+	"time"
 )
 
 const (
@@ -17,6 +18,7 @@ const (
 	layoutStackSize    = 16
 	containerPoolSize  = 48
 	treeNodePoolSize   = 48
+	storagePoolSize    = 64
 	maxWidths          = 16
 )
 
@@ -33,6 +35,22 @@ var defaultStyle Style = Style{
 	TitleHeight:   24,
 	ScrollbarSize: 12,
 	ThumbSize:     8,
+
+	TooltipDelay:      500 * time.Millisecond,
+	KeyRepeatDelay:    500 * time.Millisecond,
+	KeyRepeatInterval: 50 * time.Millisecond,
+	DoubleClickTime:   400 * time.Millisecond,
+
+	CaretBlinkInterval: 530 * time.Millisecond,
+	TransitionDuration: 150 * time.Millisecond,
+
+	FocusRingWidth: 2,
+
+	WheelScroll: 30,
+
+	FineDragFactor:   0.1,
+	CoarseDragFactor: 10,
+
 	Colors: [...]color.RGBA{
 		{230, 230, 230, 255}, // MU_COLOR_TEXT
 		{25, 25, 25, 255},    // MU_COLOR_BORDER
@@ -48,6 +66,11 @@ var defaultStyle Style = Style{
 		{40, 40, 40, 255},    // MU_COLOR_BASEFOCUS
 		{43, 43, 43, 255},    // MU_COLOR_SCROLLBASE
 		{30, 30, 30, 255},    // MU_COLOR_SCROLLTHUMB
+		{60, 90, 140, 255},   // MU_COLOR_HIGHLIGHT
+		{200, 60, 60, 255},   // MU_COLOR_ERROR
+		{255, 200, 0, 255},   // MU_COLOR_FOCUSRING
+		{0, 0, 0, 128},       // MU_COLOR_DIM
+		{60, 90, 140, 160},   // MU_COLOR_TEXTSELECTION
 	},
 }
 
@@ -0,0 +1,72 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 The Ebitengine Authors
+
+package microui
+
+import "image"
+
+// ContainerState is the state of a window retained between frames, read with
+// Context.ContainerState and changed with SetContainerState.
+type ContainerState struct {
+	Rect   image.Rectangle
+	Scroll image.Point
+	Open   bool
+	ZIndex int
+}
+
+type containerUpdate struct {
+	name  string
+	state ContainerState
+}
+
+// findWindow returns the container of the window named name, or nil if it is
+// not known.
+func (c *Context) findWindow(name string) *Container {
+	for i := range c.containers {
+		if c.containerPool[i].id != 0 && c.containers[i].name == name {
+			return &c.containers[i]
+		}
+	}
+	return nil
+}
+
+// ContainerState returns the state of the window named name, and whether the
+// window is known to the context. The changes made with SetContainerState are
+// only seen from the next frame.
+func (c *Context) ContainerState(name string) (ContainerState, bool) {
+	cnt := c.findWindow(name)
+	if cnt == nil {
+		return ContainerState{}, false
+	}
+	return ContainerState{
+		Rect:   cnt.Rect,
+		Scroll: cnt.Scroll,
+		Open:   cnt.Open,
+		ZIndex: cnt.ZIndex,
+	}, true
+}
+
+// SetContainerState sets the state of the window named name, like for a
+// "reset layout" command or to scroll a console to its top. It can be called
+// at any time: the state is set at the start of the next frame, before the
+// windows are built. A window with a higher z-index is in front, within its
+// layer.
+func (c *Context) SetContainerState(name string, s ContainerState) {
+	c.stateUpdates = append(c.stateUpdates, containerUpdate{name: name, state: s})
+}
+
+// applyContainerUpdates sets the states set with SetContainerState.
+func (c *Context) applyContainerUpdates() {
+	for _, u := range c.stateUpdates {
+		cnt := c.windowContainer(u.name)
+		cnt.Rect = u.state.Rect
+		cnt.Scroll = u.state.Scroll
+		cnt.Open = u.state.Open
+		cnt.ZIndex = u.state.ZIndex
+		c.lastZIndex = max(c.lastZIndex, cnt.ZIndex)
+	}
+	if len(c.stateUpdates) > 0 {
+		c.sortZOrder()
+	}
+	c.stateUpdates = c.stateUpdates[:0]
+}
@@ -22,7 +22,17 @@ func (c *Context) drawFrame(rect image.Rectangle, colorid int) {
 }
 
 func NewContext() *Context {
-	return &Context{
-		Style: &defaultStyle,
+	return NewContextWithPoolOptions(nil)
+}
+
+// NewContextWithPoolOptions is like NewContext, but configures the pools
+// retaining the state of the containers and tree nodes with opts.
+func NewContextWithPoolOptions(opts *PoolOptions) *Context {
+	c := &Context{
+		Style:     &defaultStyle,
+		Clipboard: &memoryClipboard{},
+		Localizer: englishLocalizer{},
 	}
+	c.initPools(opts)
+	return c
 }
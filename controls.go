@@ -4,10 +4,13 @@
 package microui
 
 import (
-	"fmt"
 	"image"
 	"math"
+	"slices"
 	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
 	"unsafe"
 )
 
@@ -29,6 +32,7 @@ func (c *Context) drawControlFrame(id ID, rect image.Rectangle, colorid int, opt
 	if (opt & OptNoFrame) != 0 {
 		return
 	}
+	defer c.readOnlyFade()()
 	if c.focus == id {
 		colorid += 2
 	} else if c.hover == id {
@@ -37,22 +41,105 @@ func (c *Context) drawControlFrame(id ID, rect image.Rectangle, colorid int, opt
 	c.drawFrame(rect, colorid)
 }
 
+// readOnlyFade fades the drawing of a control in read-only mode, and returns
+// a function restoring the fade.
+func (c *Context) readOnlyFade() func() {
+	fade := c.fade
+	if c.ReadOnly {
+		c.fade = maxF(fade, 0.5)
+	}
+	return func() {
+		c.fade = fade
+	}
+}
+
+// ellipsis truncates str with an ellipsis so that it fits in width.
+func ellipsis(str string, width int) string {
+	const dots = "..."
+	runes := []rune(str)
+	for len(runes) > 0 && textWidth(string(runes))+textWidth(dots) > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + dots
+}
+
 func (c *Context) drawControlText(str string, rect image.Rectangle, colorid int, opt Option) {
+	defer c.readOnlyFade()()
 	var pos image.Point
 	tw := textWidth(str)
+	if (opt&OptEllipsis) != 0 && tw > rect.Dx()-c.Style.Padding*2 {
+		// show the full text in a tooltip
+		full := str
+		c.tooltipAt(rect, func() {
+			c.SetLayoutRow([]int{textWidth(full) + c.Style.Padding*2}, 0)
+			c.Label(full)
+		})
+		str = ellipsis(str, rect.Dx()-c.Style.Padding*2)
+		tw = textWidth(str)
+	}
 	c.pushClipRect(rect)
 	pos.Y = rect.Min.Y + (rect.Dy()-lineHeight())/2
-	if (opt & OptAlignCenter) != 0 {
-		pos.X = rect.Min.X + (rect.Dx()-tw)/2
-	} else if (opt & OptAlignRight) != 0 {
-		pos.X = rect.Min.X + rect.Dx() - tw - c.Style.Padding
-	} else {
-		pos.X = rect.Min.X + c.Style.Padding
+	pos.X = c.controlTextX(tw, rect, opt)
+	dir := c.controlTextDir(opt)
+	if c.textHighlight != "" {
+		if i := indexFold(str, c.textHighlight); i >= 0 {
+			x := pos.X + textWidth(str[:i])
+			w := textWidth(str[i : i+len(c.textHighlight)])
+			c.drawRect(image.Rect(x, pos.Y, x+w, pos.Y+lineHeight()), c.Style.Colors[ColorHighlight])
+		}
 	}
-	c.drawText(str, pos, c.Style.Colors[colorid])
+	c.drawTextDir(str, pos, c.Style.Colors[colorid], dir)
 	c.popClipRect()
 }
 
+// controlTextDir returns the paragraph direction of the text of a control
+// with opt. The options override the direction of the style.
+func (c *Context) controlTextDir(opt Option) int {
+	switch {
+	case (opt & OptLeftToRight) != 0:
+		return textDirLTR
+	case (opt & OptRightToLeft) != 0:
+		return textDirRTL
+	case c.Style.RightToLeft:
+		return textDirRTL
+	}
+	return textDirAuto
+}
+
+// controlTextX returns the x position of a text of width tw in rect, aligned
+// with opt. The options override the alignment of the style.
+func (c *Context) controlTextX(tw int, rect image.Rectangle, opt Option) int {
+	alignRight := (opt & OptAlignRight) != 0
+	if c.Style.RightToLeft && (opt&(OptAlignLeft|OptAlignCenter)) == 0 {
+		alignRight = true
+	}
+	switch {
+	case (opt & OptAlignCenter) != 0:
+		return rect.Min.X + (rect.Dx()-tw)/2
+	case alignRight:
+		return rect.Min.X + rect.Dx() - tw - c.Style.Padding
+	}
+	return rect.Min.X + c.Style.Padding
+}
+
+// drawFocusRing draws the focus ring around rect, the area of the control
+// focused with the keyboard.
+func (c *Context) drawFocusRing(rect image.Rectangle) {
+	clr := c.Style.Colors[ColorFocusRing]
+	for i := 1; i <= c.Style.FocusRingWidth; i++ {
+		c.drawBox(rect.Inset(-i), clr)
+	}
+}
+
+// drawPendingFocusRing draws the focus ring recorded by updateControl for the
+// control focused with the keyboard, if any.
+func (c *Context) drawPendingFocusRing() {
+	if c.ringPending {
+		c.ringPending = false
+		c.drawFocusRing(c.focusRing)
+	}
+}
+
 func (c *Context) mouseOver(rect image.Rectangle) bool {
 	return c.mousePos.In(rect) && c.mousePos.In(c.clipRect()) && c.inHoverRoot()
 }
@@ -64,21 +151,44 @@ func (c *Context) updateControl(id ID, rect image.Rectangle, opt Option) {
 
 	mouseover := c.mouseOver(rect)
 
+	if c.nextModal != nil && c.rootContainer() == c.nextModal {
+		c.modalIDs = append(c.modalIDs, id)
+	}
 	if c.focus == id {
 		c.keepFocus = true
+		if c.focusByKey {
+			// the ring is drawn over the control once it is drawn
+			c.focusRing, c.ringPending = rect, true
+			if c.controlDepth == 0 {
+				c.drawPendingFocusRing()
+			}
+		}
+		if len(c.containerStack) > 0 {
+			c.focusContainer = c.CurrentContainer()
+			if c.focusMoved && c.KeyboardNavigation {
+				c.revealFocus(rect)
+			}
+		}
 	}
 	if (opt & OptNoInteract) != 0 {
 		return
 	}
+	if c.ReadOnly {
+		if c.focus == id {
+			c.SetFocus(0)
+		}
+		return
+	}
 	if mouseover && c.mouseDown == 0 {
 		c.hover = id
 	}
 
 	if c.focus == id {
-		if c.mousePressed != 0 && !mouseover {
+		// clicks outside a modal window don't move the focus
+		if c.mousePressed != 0 && !mouseover && (c.modal == nil || c.hoverRoot != nil) {
 			c.SetFocus(0)
 		}
-		if c.mouseDown == 0 && (^opt&OptHoldFocus) != 0 {
+		if c.mouseDown == 0 && (^opt&OptHoldFocus) != 0 && !c.focusByKey {
 			c.SetFocus(0)
 		}
 	}
@@ -94,8 +204,24 @@ func (c *Context) updateControl(id ID, rect image.Rectangle, opt Option) {
 
 func (c *Context) Control(id ID, opt Option, f func(r image.Rectangle) Response) Response {
 	r := c.layoutNext()
+	if id != 0 && (opt&OptNoInteract) == 0 {
+		c.addTabStop(id)
+	}
 	c.updateControl(id, r, opt)
-	return f(r)
+	if c.budget != nil {
+		c.countControl()
+	}
+	if c.profileControls && id != 0 {
+		defer c.profileStart("", id)()
+	}
+	c.controlDepth++
+	res := f(r)
+	c.controlDepth--
+	if c.controlDepth == 0 {
+		c.drawPendingFocusRing()
+	}
+	c.emitEvents(id, res)
+	return res
 }
 
 func (c *Context) Text(text string) {
@@ -131,9 +257,28 @@ func (c *Context) Text(text string) {
 	})
 }
 
-func (c *Context) Label(text string) {
-	c.Control(0, 0, func(r image.Rectangle) Response {
-		c.drawControlText(text, r, ColorText, 0)
+// copyClicked reports whether the control at r is clicked with Control held.
+// The click is only complete once the button is released without dragging,
+// so that a Control+drag still changes sliders and numbers by coarser steps.
+func (c *Context) copyClicked(r image.Rectangle) bool {
+	return c.clickReleased() && (c.keyDown&keyControl) != 0 && c.mouseOver(r)
+}
+
+// copyClick reports whether the control at r is clicked with Control held,
+// which copies text to the clipboard and drops the focus the press took.
+func (c *Context) copyClick(r image.Rectangle, text string) bool {
+	if !c.copyClicked(r) {
+		return false
+	}
+	c.Clipboard.SetText(text)
+	c.SetFocus(0)
+	return true
+}
+
+func (c *Context) LabelEx(text string, opt Option) {
+	c.Control(0, opt, func(r image.Rectangle) Response {
+		c.copyClick(r, text)
+		c.drawControlText(text, r, ColorText, opt)
 		return 0
 	})
 }
@@ -146,8 +291,15 @@ func (c *Context) buttonEx(label string, opt Option) Response {
 	return c.Control(id, opt, func(r image.Rectangle) Response {
 		var res Response
 		// handle click
-		if c.mousePressed == mouseLeft && c.focus == id {
+		if c.activated(id) {
 			res |= ResponseSubmit
+			c.repeatAt = c.now + c.Style.KeyRepeatDelay
+		} else if (opt&OptRepeat) != 0 && c.focus == id && c.mouseDown == mouseLeft && c.mouseOver(r) {
+			// repeat while held, as a held key does
+			if c.now >= c.repeatAt {
+				res |= ResponseSubmit
+				c.repeatAt = max64(c.repeatAt+c.Style.KeyRepeatInterval, c.now)
+			}
 		}
 		// draw
 		c.drawControlFrame(id, r, ColorButton, opt)
@@ -160,14 +312,19 @@ func (c *Context) buttonEx(label string, opt Option) Response {
 
 func (c *Context) Checkbox(label string, state *bool) Response {
 	id := c.id(ptrToBytes(unsafe.Pointer(state)))
+	return c.checkbox(label, id, state)
+}
+
+func (c *Context) checkbox(label string, id ID, state *bool) Response {
 	return c.Control(id, 0, func(r image.Rectangle) Response {
 		var res Response
 		box := image.Rect(r.Min.X, r.Min.Y, r.Min.X+r.Dy(), r.Max.Y)
 		c.updateControl(id, r, 0)
 		// handle click
-		if c.mousePressed == mouseLeft && c.focus == id {
+		if c.activated(id) {
 			res |= ResponseChange
 			*state = !*state
+			c.eventValues(!*state, *state)
 		}
 		// draw
 		c.drawControlFrame(id, box, ColorBase, 0)
@@ -180,22 +337,203 @@ func (c *Context) Checkbox(label string, state *bool) Response {
 	})
 }
 
-func (c *Context) textBoxRaw(buf *string, id ID, opt Option) Response {
+// CheckboxTri is a check box for a state which may be mixed, like a "select
+// all" check box over partially selected items. A click checks a mixed or
+// unchecked state, and unchecks a checked one.
+func (c *Context) CheckboxTri(label string, state *TriState) Response {
+	id := c.id(ptrToBytes(unsafe.Pointer(state)))
+	return c.Control(id, 0, func(r image.Rectangle) Response {
+		var res Response
+		box := image.Rect(r.Min.X, r.Min.Y, r.Min.X+r.Dy(), r.Max.Y)
+		// handle click
+		if c.activated(id) {
+			old := *state
+			if *state == TriChecked {
+				*state = TriUnchecked
+			} else {
+				*state = TriChecked
+			}
+			res |= ResponseChange
+			c.eventValues(old, *state)
+		}
+		// draw
+		c.drawControlFrame(id, box, ColorBase, 0)
+		c.drawCheckState(box, *state)
+		r = image.Rect(r.Min.X+box.Dx(), r.Min.Y, r.Max.X, r.Max.Y)
+		c.drawControlText(label, r, ColorText, 0)
+		return res
+	})
+}
+
+// FilterNumeric accepts the runes of a decimal number.
+func FilterNumeric(r rune) bool {
+	return (r >= '0' && r <= '9') || strings.ContainsRune("+-.eE", r)
+}
+
+// FilterIdentifier accepts the runes of an identifier.
+func FilterIdentifier(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// textInputFor returns the runes of text accepted by the text box config for
+// the buffer buf.
+func (c *Context) textInputFor(buf string, text []rune, cfg textBoxConfig) []rune {
+	if cfg.maxLen <= 0 && cfg.filter == nil {
+		return text
+	}
+	n := utf8.RuneCountInString(buf)
+	var input []rune
+	for _, r := range text {
+		if cfg.maxLen > 0 && n+len(input) >= cfg.maxLen {
+			break
+		}
+		if cfg.filter != nil && !cfg.filter(r) {
+			continue
+		}
+		input = append(input, r)
+	}
+	return input
+}
+
+// pastedRunes returns the runes of the text s pasted in a text box, without
+// the line breaks and the other control characters.
+func pastedRunes(s string) []rune {
+	var rs []rune
+	for _, r := range s {
+		if !unicode.IsControl(r) {
+			rs = append(rs, r)
+		}
+	}
+	return rs
+}
+
+// passwordMask is shown for each character of a text box with OptPassword.
+const passwordMask = "*"
+
+// maskPassword returns the mask shown for the password buf, and the position
+// in the mask of the byte offset pos of buf.
+func maskPassword(buf string, pos int) (string, int) {
+	var n, p int
+	for i := 0; i < len(buf); i = nextGrapheme(buf, i) {
+		if i < pos {
+			p++
+		}
+		n++
+	}
+	return strings.Repeat(passwordMask, n), p * len(passwordMask)
+}
+
+// unmaskPassword returns the byte offset in the password buf of the position
+// pos of its mask.
+func unmaskPassword(buf string, pos int) int {
+	var i int
+	for k := 0; k < pos/len(passwordMask) && i < len(buf); k++ {
+		i = nextGrapheme(buf, i)
+	}
+	return i
+}
+
+// textBoxRaw is a text box for buf.
+// Escape cancels the edit, restoring the value buf had when the text box got
+// the focus, and reports ResponseCancel.
+// With OptDeferChange, ResponseChange is only reported when the edit is
+// committed with Enter or by losing the focus.
+// With OptPassword, the text is masked and can't be copied.
+func (c *Context) textBoxRaw(buf *string, id ID, cfg textBoxConfig, opt Option) Response {
 	return c.Control(id, opt|OptHoldFocus, func(r image.Rectangle) Response {
 		var res Response
-		buflen := len(*buf)
+		last := *buf
+		password := (opt & OptPassword) != 0
+
+		if c.focus == id && c.textEdit != id {
+			c.textEdit = id
+			c.textEditOrig = *buf
+			c.textCaret = len(*buf)
+			c.textAnchor = c.textCaret
+			c.composition = ""
+		}
 
 		if c.focus == id {
-			// handle text input
-			if len(c.textInput) > 0 {
-				*buf += string(c.textInput)
-				res |= ResponseChange
+			// the keys go to the input method while composing
+			if c.composition != "" {
+				c.keyPressed &^= keyBackspace | keyDelete | keyLeft | keyRight | keyHome | keyEnd | keyReturn | keyEscape
+			}
+			// the text may have been changed by the application, so keep the
+			// positions within it, on whole runes
+			c.textCaret = runeStart(*buf, c.textCaret)
+			c.textAnchor = runeStart(*buf, c.textAnchor)
+			shift := (c.keyDown & keyShift) != 0
+			// handle clicks and drags, moving the caret and selecting text
+			if c.mouseDown == mouseLeft && (c.mousePressed == 0 || c.mouseOver(r)) {
+				view, caret := *buf, c.textCaret
+				if password {
+					view, caret = maskPassword(*buf, c.textCaret)
+				}
+				c.textCaret = textIndexAt(view, c.mousePos.X-c.textBoxTextX(id, view, caret, r, opt), c.controlTextDir(opt))
+				if password {
+					c.textCaret = unmaskPassword(*buf, c.textCaret)
+				}
+				if c.mousePressed == mouseLeft && !shift {
+					c.textAnchor = c.textCaret
+				}
 			}
-			// handle backspace
-			if (c.keyPressed&keyBackspace) != 0 && buflen > 0 {
-				*buf = (*buf)[:buflen-1]
+			sel := textSelection{anchor: c.textAnchor, caret: c.textCaret}
+			start, end := sel.bounds()
+			// handle copy, cut and paste; a password can't be copied
+			ctrl := (c.keyDown & keyControl) != 0
+			if ctrl && (c.keyPressed&(keyC|keyX)) != 0 && start < end && !password {
+				c.Clipboard.SetText((*buf)[start:end])
+			}
+			text := c.textInput
+			if ctrl && (c.keyPressed&keyV) != 0 {
+				text = append(slices.Clone(text), pastedRunes(c.Clipboard.Text())...)
+			}
+			cut := ctrl && (c.keyPressed&keyX) != 0 && !password
+			// handle text input, replacing the selection
+			if input := c.textInputFor((*buf)[:start]+(*buf)[end:], text, cfg); len(input) > 0 {
+				s := string(input)
+				*buf = (*buf)[:start] + s + (*buf)[end:]
+				c.textCaret = start + len(s)
+				c.textAnchor = c.textCaret
+				res |= ResponseChange
+			} else if ((c.keyPressed&(keyBackspace|keyDelete)) != 0 || cut) && start < end {
+				// handle backspace, delete and cut, removing the selection
+				*buf = (*buf)[:start] + (*buf)[end:]
+				c.textCaret, c.textAnchor = start, start
+				res |= ResponseChange
+			} else if (c.keyPressed&keyBackspace) != 0 && c.textCaret > 0 {
+				prev := prevGrapheme(*buf, c.textCaret)
+				*buf = (*buf)[:prev] + (*buf)[c.textCaret:]
+				c.textCaret, c.textAnchor = prev, prev
 				res |= ResponseChange
+			} else if (c.keyPressed&keyDelete) != 0 && c.textCaret < len(*buf) {
+				*buf = (*buf)[:c.textCaret] + (*buf)[nextGrapheme(*buf, c.textCaret):]
+				c.textAnchor = c.textCaret
+				res |= ResponseChange
+			}
+			// handle caret movement, extending the selection with Shift
+			moved := true
+			switch {
+			case (c.keyPressed&keyLeft) != 0 && start < end && !shift:
+				c.textCaret = start
+			case (c.keyPressed&keyRight) != 0 && start < end && !shift:
+				c.textCaret = end
+			case (c.keyPressed&keyLeft) != 0 && c.textCaret > 0:
+				c.textCaret = prevGrapheme(*buf, c.textCaret)
+			case (c.keyPressed&keyRight) != 0 && c.textCaret < len(*buf):
+				c.textCaret = nextGrapheme(*buf, c.textCaret)
+			case (c.keyPressed & keyHome) != 0:
+				c.textCaret = 0
+			case (c.keyPressed & keyEnd) != 0:
+				c.textCaret = len(*buf)
+			default:
+				moved = false
+			}
+			if moved && !shift {
+				c.textAnchor = c.textCaret
 			}
+			// consume the keys so that they don't scroll the container
+			c.keyPressed &^= keyHome | keyEnd | keyPageUp | keyPageDown
 			// handle return
 			if (c.keyPressed & keyReturn) != 0 {
 				c.SetFocus(0)
@@ -203,19 +541,73 @@ func (c *Context) textBoxRaw(buf *string, id ID, opt Option) Response {
 			}
 		}
 
+		// handle cancel and commit
+		deferred := (opt & OptDeferChange) != 0
+		if deferred {
+			res &^= ResponseChange
+		}
+		if c.textEdit == id {
+			if c.focus == id && (c.keyPressed&keyEscape) != 0 {
+				if !deferred && *buf != c.textEditOrig {
+					res |= ResponseChange
+				}
+				*buf = c.textEditOrig
+				c.SetFocus(0)
+				c.textEdit = 0
+				res |= ResponseCancel
+			} else if (res&ResponseSubmit) != 0 || c.focus != id {
+				if deferred && *buf != c.textEditOrig {
+					res |= ResponseChange
+				}
+				c.textEdit = 0
+			}
+		}
+		if (res & ResponseChange) != 0 {
+			if deferred {
+				last = c.textEditOrig
+			}
+			c.eventValues(last, *buf)
+		} else if (res & ResponseSubmit) != 0 {
+			c.eventValues(*buf, *buf)
+		}
+
 		// draw
 		c.drawControlFrame(id, r, ColorBase, opt)
 		if c.focus == id {
 			color := c.Style.Colors[ColorText]
-			textw := textWidth(*buf)
 			texth := lineHeight()
-			ofx := r.Dx() - c.Style.Padding - textw - 1
-			textx := r.Min.X + min(ofx, c.Style.Padding)
+			text, caret := c.compositionText(*buf, c.textCaret)
+			start, end := textSelection{anchor: c.textAnchor, caret: c.textCaret}.bounds()
+			selText := *buf
+			if password {
+				// a mask is shown instead of the text
+				text, caret = maskPassword(*buf, c.textCaret)
+				_, start = maskPassword(*buf, start)
+				_, end = maskPassword(*buf, end)
+				selText = text
+			}
+			// the text, the selection and the caret are placed like the text
+			// of the unfocused text box
+			dir := c.controlTextDir(opt)
+			textx := c.textBoxTextX(id, text, caret, r, opt)
 			texty := r.Min.Y + (r.Dy()-texth)/2
 			c.pushClipRect(r)
-			c.drawText(*buf, image.Pt(textx, texty), color)
-			c.drawRect(image.Rect(textx+textw, texty, textx+textw+1, texty+texth), color)
+			c.drawTextSelection(selText, image.Pt(textx, texty), start, end, dir)
+			c.drawTextDir(text, image.Pt(textx, texty), color, dir)
+			if c.composition != "" && !password {
+				// underline the composed text
+				x0, x1 := caretX(text, c.textCaret, dir), caretX(text, caret, dir)
+				c.drawRect(image.Rect(textx+min(x0, x1), texty+texth-1, textx+max(x0, x1), texty+texth), color)
+			}
+			caretx := textx + caretX(text, caret, dir)
+			c.textCaretRect = image.Rect(caretx, texty, caretx+1, texty+texth)
+			if c.caretVisible(id, caret, len(text)) {
+				c.drawRect(c.textCaretRect, color)
+			}
 			c.popClipRect()
+		} else if password {
+			mask, _ := maskPassword(*buf, 0)
+			c.drawControlText(mask, r, ColorText, opt)
 		} else {
 			c.drawControlText(*buf, r, ColorText, opt)
 		}
@@ -223,50 +615,395 @@ func (c *Context) textBoxRaw(buf *string, id ID, opt Option) Response {
 	})
 }
 
-func (c *Context) numberTextBox(value *float64, id ID) bool {
-	if c.mousePressed == mouseLeft && (c.keyDown&keyShift) != 0 &&
-		c.hover == id {
+// caretVisible reports whether the caret at pos in the text of length n of the
+// control id is shown, blinking at the caret blink interval. The caret is
+// shown without blinking for an interval after it moves or the text changes.
+func (c *Context) caretVisible(id ID, pos, n int) bool {
+	if c.Style.CaretBlinkInterval <= 0 {
+		return true
+	}
+	if s := (caretState{id: id, pos: pos, n: n}); s != c.caretState {
+		c.caretState = s
+		c.caretBlinkAt = c.now
+	}
+	return ((c.now-c.caretBlinkAt)/c.Style.CaretBlinkInterval)%2 == 0
+}
+
+// textBoxTextX returns the position of the text buf in the focused text box
+// id at r with opt. A text fitting in the text box is aligned with opt, and a
+// longer one is scrolled horizontally only as much as needed to keep the caret
+// visible, with the scroll kept in the state of id.
+func (c *Context) textBoxTextX(id ID, buf string, caret int, r image.Rectangle, opt Option) int {
+	s := WidgetState[int](c.Widget(), fnv1a(id, []byte("!scroll")))
+	w := r.Dx() - c.Style.Padding*2 - 1
+	if tw := textWidth(buf); tw <= w {
+		*s = 0
+		return c.controlTextX(tw, r, opt)
+	}
+	scroll := *s
+	x := caretX(buf, caret, c.controlTextDir(opt))
+	if x-scroll > w {
+		scroll = x - w
+	}
+	if x < scroll {
+		scroll = x
+	}
+	// don't show an empty space after the end of the text
+	scroll = clamp(scroll, 0, max(textWidth(buf)-w, 0))
+	*s = scroll
+	return r.Min.X + c.Style.Padding - scroll
+}
+
+// formatEditValue formats value for editing with the precision of the
+// format, or with the smallest number of digits representing it exactly if the
+// format has no precision.
+func formatEditValue(value float64, format string) string {
+	if i := strings.Index(format, "%."); i >= 0 {
+		j := i + 2
+		for j < len(format) && format[j] >= '0' && format[j] <= '9' {
+			j++
+		}
+		if prec, err := strconv.Atoi(format[i+2 : j]); err == nil && j < len(format) && format[j] == 'f' {
+			return strconv.FormatFloat(value, 'f', prec, 64)
+		}
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// formatUnit returns the unit following the verb of format, like "px" for
+// "%.0f px".
+func formatUnit(format string) string {
+	i := strings.Index(format, "%")
+	for i >= 0 && i+1 < len(format) && format[i+1] == '%' {
+		// skip the escaped percent signs before the verb
+		j := strings.Index(format[i+2:], "%")
+		if j < 0 {
+			return ""
+		}
+		i += 2 + j
+	}
+	if i < 0 {
+		return ""
+	}
+	j := i + 1
+	for j < len(format) && strings.IndexByte("+-# 0123456789.", format[j]) >= 0 {
+		j++
+	}
+	if j >= len(format) {
+		return ""
+	}
+	return strings.TrimSpace(strings.ReplaceAll(format[j+1:], "%%", "%"))
+}
+
+// numberTextBox handles the text input mode of a number control, and reports
+// whether it is active. The edited value is committed to value with Enter or
+// by losing the focus, and Escape cancels the edit.
+//
+// The text input mode starts with a double click, or with a Shift+click which
+// doesn't drag, as a Shift+drag changes the value by finer steps.
+//
+// The value is edited with the precision of format, and with OptExpression,
+// simple arithmetic expressions like "1920/2" are evaluated on commit. A text
+// which isn't a finite number keeps the value.
+func (c *Context) numberTextBox(value *float64, id ID, format valueFormat, opt Option) (Response, bool) {
+	if (c.mousePressed == mouseLeft && c.doubleClicked && c.hover == id) ||
+		((c.keyDown&keyShift) != 0 && c.clickReleased() && c.focus == id) {
 		c.numberEdit = id
-		c.numberEditBuf = fmt.Sprintf(realFmt, *value)
+		c.numberEditBuf = format.editText(*value)
 	}
 	if c.numberEdit == id {
-		res := c.textBoxRaw(&c.numberEditBuf, id, 0)
+		filter := FilterNumeric
+		if (opt & OptExpression) != 0 {
+			filter = FilterExpression
+		}
+		// the unit of the format can be typed, and is ignored
+		unit := format.unit()
+		cfg := textBoxConfig{filter: func(r rune) bool {
+			return filter(r) || strings.ContainsRune(unit, r)
+		}}
+		// the edited text is not the value of the control
+		c.muteEvents++
+		res := c.textBoxRaw(&c.numberEditBuf, id, cfg, 0)
+		c.muteEvents--
+		if (res & ResponseCancel) != 0 {
+			c.numberEdit = 0
+			return ResponseCancel, true
+		}
 		if (res&ResponseSubmit) != 0 || c.focus != id {
-			nval, err := strconv.ParseFloat(c.numberEditBuf, 32)
-			if err != nil {
-				nval = 0
+			var nval float64
+			var err error
+			str := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(c.numberEditBuf), unit))
+			if (opt & OptExpression) != 0 {
+				nval, err = evalExpr(str)
+			} else {
+				nval, err = strconv.ParseFloat(str, 64)
+			}
+			// an invalid text keeps the value
+			if err != nil || math.IsNaN(nval) || math.IsInf(nval, 0) {
+				nval = *value
 			}
-			*value = float64(nval)
+			last := *value
+			*value = nval
 			c.numberEdit = 0
+			if *value != last {
+				c.eventValues(last, *value)
+				c.emitEvents(id, ResponseChange)
+				return ResponseChange, true
+			}
 		}
-		return true
+		return 0, true
 	}
-	return false
+	return 0, false
 }
 
-func (c *Context) textBoxEx(buf *string, opt Option) Response {
+func (c *Context) textBox(buf *string, cfg textBoxConfig, opt Option) Response {
 	id := c.id(ptrToBytes(unsafe.Pointer(buf)))
-	return c.textBoxRaw(buf, id, opt)
+	return c.textBoxRaw(buf, id, cfg, opt)
 }
 
-func (c *Context) SliderEx(value *float64, low, high, step float64, format string, opt Option) Response {
+// dragFactor returns the factor applied to the drag steps for the modifier
+// keys held.
+func (c *Context) dragFactor() float64 {
+	if (c.keyDown & keyShift) != 0 {
+		return c.Style.FineDragFactor
+	}
+	if (c.keyDown & (keyControl | keyAlt)) != 0 {
+		return c.Style.CoarseDragFactor
+	}
+	return 1
+}
+
+// keySteps returns the number of steps a focused value control is adjusted by
+// with the arrow and page keys pressed this frame.
+func (c *Context) keySteps() float64 {
+	var n float64
+	if (c.keyPressed & (keyRight | keyUp)) != 0 {
+		n++
+	}
+	if (c.keyPressed & (keyLeft | keyDown)) != 0 {
+		n--
+	}
+	if (c.keyPressed & keyPageUp) != 0 {
+		n += 10
+	}
+	if (c.keyPressed & keyPageDown) != 0 {
+		n -= 10
+	}
+	// consume the keys so that they don't scroll the container, nor move the
+	// focus in a popup
+	c.keyPressed &^= keyPageUp | keyPageDown | keyUp | keyDown
+	return n
+}
+
+// sliderKey returns the value v of a focused slider adjusted with the keyboard.
+func (c *Context) sliderKey(v, low, high, step float64, ticks []float64, opt Option) float64 {
+	home, end := (c.keyPressed&keyHome) != 0, (c.keyPressed&keyEnd) != 0
+	// consume the keys so that they don't scroll the container
+	c.keyPressed &^= keyHome | keyEnd
+	if home {
+		return low
+	}
+	if end {
+		return high
+	}
+	n := c.keySteps()
+	if n == 0 {
+		return v
+	}
+	if len(ticks) > 0 {
+		i := 0
+		for j, t := range ticks {
+			if math.Abs(t-v) < math.Abs(ticks[i]-v) {
+				i = j
+			}
+		}
+		return ticks[clamp(i+int(n), 0, len(ticks)-1)]
+	}
+	log := (opt & OptLogarithmic) != 0
+	if log {
+		v, low, high = math.Log10(v), math.Log10(low), math.Log10(high)
+	}
+	s := step
+	if s == 0 {
+		s = (high - low) / 100
+	}
+	v += n * s
+	if step != 0 {
+		v = math.Round(v/step) * step
+	}
+	if log {
+		return math.Pow(10, v)
+	}
+	return v
+}
+
+// sliderValue returns the value at the ratio t of the slider track.
+func sliderValue(t, low, high, step float64, opt Option) float64 {
+	if (opt & OptLogarithmic) != 0 {
+		// step is applied to the decimal exponent
+		e := math.Log10(low) + t*math.Log10(high/low)
+		if step != 0 {
+			e = math.Round(e/step) * step
+		}
+		return math.Pow(10, e)
+	}
+	v := low + t*(high-low)
+	if step != 0 {
+		v = math.Round(v/step) * step
+	}
+	return v
+}
+
+// sliderRatio returns the ratio of the slider track at the value v.
+func sliderRatio(v, low, high float64, opt Option) float64 {
+	if (opt & OptLogarithmic) != 0 {
+		return math.Log(v/low) / math.Log(high/low)
+	}
+	return (v - low) / (high - low)
+}
+
+// sliderTicks returns the values of the tick marks at every step between low
+// and high, or nil if they would be too dense to be drawn in a track of the
+// width w.
+func sliderTicks(low, high, step float64, w int, opt Option) []float64 {
+	if step <= 0 {
+		return nil
+	}
+	from, to := low, high
+	if (opt & OptLogarithmic) != 0 {
+		from, to = math.Log10(low), math.Log10(high)
+	}
+	// the number of ticks is rounded so that the last one isn't dropped by the
+	// rounding errors of the steps
+	const eps = 1e-9
+	from = math.Ceil(from/step-eps) * step
+	n := int(math.Floor((to-from)/step + eps))
+	if n > w/4 {
+		return nil
+	}
+	var ticks []float64
+	for i := 0; i <= n; i++ {
+		v := from + float64(i)*step
+		if (opt & OptLogarithmic) != 0 {
+			v = math.Pow(10, v)
+		}
+		ticks = append(ticks, v)
+	}
+	return ticks
+}
+
+// drawSliderTicks draws the tick marks of a slider at r with the value v, and
+// the labels of the ticks if labels is not nil. The label of the tick the
+// value is snapped to is emphasized, and the labels overlapping the previous
+// one are left out.
+func (c *Context) drawSliderTicks(r image.Rectangle, v, low, high float64, ticks []float64, labels []string, opt Option) {
+	w := c.Style.ThumbSize
+	var lastLabel image.Rectangle
+	for i, t := range ticks {
+		if t < low || t > high {
+			continue
+		}
+		snapped := math.Abs(t-v) <= 1e-9*maxF(math.Abs(v), 1)
+		if i < len(labels) && labels[i] != "" {
+			lr := c.sliderTickLabel(r, sliderRatio(t, low, high, opt), labels[i], opt)
+			if !lr.Overlaps(lastLabel) {
+				clr := c.Style.Colors[ColorText]
+				if !snapped {
+					clr.R, clr.G, clr.B, clr.A = clr.R/2, clr.G/2, clr.B/2, clr.A/2
+				}
+				c.pushClipRect(r)
+				c.drawText(labels[i], lr.Min, clr)
+				c.popClipRect()
+				lastLabel = lr
+			}
+		}
+		var tick image.Rectangle
+		// a vertical slider has its ticks on the right, from the bottom
+		out := image.Pt(0, -2)
+		if (opt & OptVertical) != 0 {
+			y := r.Max.Y - w/2 - int(sliderRatio(t, low, high, opt)*float64(r.Dy()-w))
+			tick = image.Rect(r.Max.X-3, y-1, r.Max.X, y)
+			out = image.Pt(-2, 0)
+		} else {
+			x := r.Min.X + w/2 + int(sliderRatio(t, low, high, opt)*float64(r.Dx()-w))
+			tick = image.Rect(x, r.Max.Y-3, x+1, r.Max.Y)
+		}
+		// emphasize the tick the value is snapped to
+		if snapped {
+			c.drawRect(tick.Add(out).Union(tick), c.Style.Colors[ColorText])
+		} else {
+			c.drawRect(tick, c.Style.Colors[ColorButton])
+		}
+	}
+}
+
+// sliderTickLabel returns the rectangle of the label of the tick at the ratio
+// t of the track of the slider at r, centered on the tick.
+func (c *Context) sliderTickLabel(r image.Rectangle, t float64, label string, opt Option) image.Rectangle {
+	w := c.Style.ThumbSize
+	lw, lh := textWidth(label), lineHeight()
+	if (opt & OptVertical) != 0 {
+		y := r.Max.Y - w/2 - int(t*float64(r.Dy()-w))
+		return image.Rect(r.Min.X+(r.Dx()-lw)/2, y-lh/2, r.Min.X+(r.Dx()+lw)/2, y-lh/2+lh)
+	}
+	x := r.Min.X + w/2 + int(t*float64(r.Dx()-w))
+	y := r.Min.Y + (r.Dy()-lh)/2
+	return image.Rect(x-lw/2, y, x-lw/2+lw, y+lh)
+}
+
+// slider builds a slider for value. If labels is not nil, they are the labels
+// of ticks, drawn at the ticks instead of the value.
+func (c *Context) slider(value *float64, id ID, low, high, step float64, ticks []float64, labels []string, format valueFormat, opt Option) Response {
 	last := *value
 	v := last
-	id := c.id(ptrToBytes(unsafe.Pointer(value)))
 
 	// handle text input mode
-	if c.numberTextBox(&v, id) {
-		return 0
+	if res, ok := c.numberTextBox(value, id, format, opt); ok {
+		return res
 	}
 
 	// handle normal mode
-	return c.Control(id, opt, func(r image.Rectangle) Response {
+	vertical := (opt & OptVertical) != 0
+	return c.Control(id, opt|OptHoldFocus, func(r image.Rectangle) Response {
 		var res Response
+		// the length of the track, along which the value goes up
+		length := r.Dx()
+		if vertical {
+			length = r.Dy()
+		}
 		// handle input
+		c.copyValueClick(r, format, v)
+		if c.focus == id {
+			v = c.sliderKey(v, low, high, step, ticks, opt)
+		}
 		if c.focus == id && (c.mouseDown|c.mousePressed) == mouseLeft {
-			v = low + float64(c.mousePos.X-r.Min.X)*(high-low)/float64(r.Dx())
-			if step != 0 {
-				v = math.Round(v/step) * step
+			// the thumb follows the cursor, unless a modifier is held to move
+			// it relatively by finer or coarser steps
+			f := c.dragFactor()
+			pos, delta := c.mousePos.X-r.Min.X, c.mouseDelta.X
+			if vertical {
+				pos, delta = r.Max.Y-c.mousePos.Y, -c.mouseDelta.Y
+			}
+			if f == 1 {
+				c.dragRatio = float64(pos) / float64(length)
+			} else if c.mousePressed != 0 {
+				// a relative drag starts at the value, without jumping
+				c.dragRatio = sliderRatio(v, low, high, opt)
+			} else {
+				c.dragRatio = clampF(c.dragRatio+float64(delta)/float64(length)*f, 0, 1)
+			}
+			t := c.dragRatio
+			if len(ticks) > 0 {
+				// snap to the nearest tick
+				nearest := ticks[0]
+				for _, tick := range ticks[1:] {
+					if math.Abs(sliderRatio(tick, low, high, opt)-t) < math.Abs(sliderRatio(nearest, low, high, opt)-t) {
+						nearest = tick
+					}
+				}
+				v = nearest
+			} else {
+				v = sliderValue(t, low, high, step*f, opt)
 			}
 		}
 		// clamp and store value, update res
@@ -274,57 +1011,199 @@ func (c *Context) SliderEx(value *float64, low, high, step float64, format strin
 		v = *value
 		if last != v {
 			res |= ResponseChange
+			c.eventValues(last, v)
 		}
 
 		// draw base
 		c.drawControlFrame(id, r, ColorBase, opt)
+		// draw tick marks
+		if len(ticks) > 0 {
+			c.drawSliderTicks(r, v, low, high, ticks, labels, opt)
+		} else if (opt & OptTicks) != 0 {
+			c.drawSliderTicks(r, v, low, high, sliderTicks(low, high, step, length, opt), nil, opt)
+		}
 		// draw thumb
 		w := c.Style.ThumbSize
-		x := int((v - low) * float64(r.Dx()-w) / (high - low))
+		x := int(sliderRatio(v, low, high, opt) * float64(length-w))
 		thumb := image.Rect(r.Min.X+x, r.Min.Y, r.Min.X+x+w, r.Max.Y)
+		if vertical {
+			thumb = image.Rect(r.Min.X, r.Max.Y-x-w, r.Max.X, r.Max.Y-x)
+		}
 		c.drawControlFrame(id, thumb, ColorButton, opt)
-		// draw text
-		text := fmt.Sprintf(format, v)
-		c.drawControlText(text, r, ColorText, opt)
+		// draw text, unless the labels of the ticks are drawn
+		if labels == nil {
+			text := c.formatValue(id, format, v)
+			c.drawControlText(text, r, ColorText, opt)
+		}
 
 		return res
 	})
 }
 
+// SliderEx is a slider for a value between low and high.
+// With OptLogarithmic, low must be positive and the value is mapped
+// logarithmically to the track; step is then a step of the decimal exponent,
+// e.g. 0.1 gives 10 values per decade.
+// With OptTicks, a tick mark is drawn at every step.
+// With OptVertical, the value goes from the bottom to the top of the slider,
+// whose height is the height of the row.
+func (c *Context) SliderEx(value *float64, low, high, step float64, format string, opt Option) Response {
+	id := c.id(ptrToBytes(unsafe.Pointer(value)))
+	return c.slider(value, id, low, high, step, nil, nil, valueFormat{verb: format}, opt)
+}
+
+// SliderTicksEx is a slider whose value snaps to the given tick values, which
+// are drawn as tick marks under the track. ticks must be sorted in increasing
+// order. If labels is not nil, labels[i] is drawn at ticks[i] instead of the
+// value, like the names of quality levels, emphasized when the value is
+// snapped to it.
+func (c *Context) SliderTicksEx(value *float64, low, high float64, ticks []float64, labels []string, format string, opt Option) Response {
+	id := c.id(ptrToBytes(unsafe.Pointer(value)))
+	return c.slider(value, id, low, high, 0, ticks, labels, valueFormat{verb: format}, opt)
+}
+
 func (c *Context) NumberEx(value *float64, step float64, format string, opt Option) Response {
 	id := c.id(ptrToBytes(unsafe.Pointer(value)))
+	return c.number(value, id, step, valueFormat{verb: format}, opt)
+}
+
+func (c *Context) number(value *float64, id ID, step float64, format valueFormat, opt Option) Response {
 	last := *value
 
 	// handle text input mode
-	if c.numberTextBox(value, id) {
-		return 0
+	if res, ok := c.numberTextBox(value, id, format, opt); ok {
+		return res
 	}
 
 	// handle normal mode
-	return c.Control(id, opt, func(r image.Rectangle) Response {
+	return c.Control(id, opt|OptHoldFocus, func(r image.Rectangle) Response {
 		var res Response
 		// handle input
+		c.copyValueClick(r, format, *value)
+		if c.focus == id {
+			*value += c.keySteps() * step
+		}
 		if c.focus == id && c.mouseDown == mouseLeft {
-			*value += float64(c.mouseDelta.X) * step
+			*value += float64(c.mouseDelta.X) * step * c.dragFactor()
 		}
 		// set flag if value changed
 		if *value != last {
 			res |= ResponseChange
+			c.eventValues(last, *value)
 		}
 
 		// draw base
 		c.drawControlFrame(id, r, ColorBase, opt)
 		// draw text
-		text := fmt.Sprintf(format, *value)
+		text := c.formatValue(id, format, *value)
 		c.drawControlText(text, r, ColorText, opt)
 
 		return res
 	})
 }
 
-func (c *Context) header(label string, istreenode bool, opt Option) Response {
+func (c *Context) drawCheckState(rect image.Rectangle, state TriState) {
+	switch state {
+	case TriChecked:
+		c.drawIcon(iconCheck, rect, c.Style.Colors[ColorText])
+	case TriMixed:
+		r := rect.Inset(rect.Dx() / 4)
+		r.Min.Y = (rect.Min.Y+rect.Max.Y)/2 - 1
+		r.Max.Y = r.Min.Y + 2
+		c.drawRect(r, c.Style.Colors[ColorText])
+	}
+}
+
+// applyTreeNodeRequest applies the pending expansion request for the node id,
+// either set for the node itself or inherited from a recursive request of an
+// ancestor. Recursive requests are kept until the node's children are built.
+func (c *Context) applyTreeNodeRequest(id ID, istreenode bool, opt Option) int {
+	idx := c.poolGet(c.treeNodePool, id)
+	if len(c.treeNodeRequests) == 0 {
+		return idx
+	}
+
+	req, ok := c.treeNodeRequests[id]
+	if !ok {
+		for i := len(c.idStack) - 1; i >= 0; i-- {
+			if r, ok := c.treeNodeRequests[c.idStack[i]]; ok && r.recursive {
+				req = r
+				req.pending = true
+				c.treeNodeRequests[id] = req
+				break
+			}
+		}
+	}
+	if !req.pending {
+		return idx
+	}
+	if req.recursive && istreenode {
+		req.pending = false
+		c.treeNodeRequests[id] = req
+	} else {
+		delete(c.treeNodeRequests, id)
+	}
+
+	active := req.expanded
+	if (opt & OptExpanded) != 0 {
+		active = !active
+	}
+	if active && idx < 0 {
+		return c.poolInit(c.treeNodePool, id)
+	}
+	if !active && idx >= 0 {
+		c.treeNodePool[idx] = poolItem{}
+		return -1
+	}
+	return idx
+}
+
+func (c *Context) setTreeNodeExpanded(id ID, expanded, recursive bool) {
+	if c.treeNodeRequests == nil {
+		c.treeNodeRequests = map[ID]treeNodeRequest{}
+	}
+	c.treeNodeRequests[id] = treeNodeRequest{
+		expanded:  expanded,
+		recursive: recursive,
+		pending:   true,
+	}
+}
+
+// clearTreeNodeRequests removes the requests applied this frame. A recursive
+// request is kept while the subtree of its node is built, and must not apply
+// to the nodes of a collapsed subtree built in a later frame.
+func (c *Context) clearTreeNodeRequests() {
+	for id, req := range c.treeNodeRequests {
+		if !req.pending {
+			delete(c.treeNodeRequests, id)
+		}
+	}
+}
+
+// TreeNodeID returns the ID a tree node or header with the given label gets
+// when it is built at this point.
+func (c *Context) TreeNodeID(label string) ID {
+	last := c.LastID
 	id := c.id([]byte(label))
-	idx := c.poolGet(c.treeNodePool[:], id)
+	c.LastID = last
+	return id
+}
+
+// SetTreeNodeExpanded expands or collapses the tree node or header id the
+// next time it is built.
+func (c *Context) SetTreeNodeExpanded(id ID, expanded bool) {
+	c.setTreeNodeExpanded(id, expanded, false)
+}
+
+// SetTreeNodeExpandedRecursive is like SetTreeNodeExpanded, but also applies
+// to all the nodes under id.
+func (c *Context) SetTreeNodeExpandedRecursive(id ID, expanded bool) {
+	c.setTreeNodeExpanded(id, expanded, true)
+}
+
+func (c *Context) header(label string, istreenode bool, state *TriState, opt Option) Response {
+	id := c.id([]byte(label))
+	idx := c.applyTreeNodeRequest(id, istreenode, opt)
 	c.SetLayoutRow([]int{-1}, 0)
 
 	active := idx >= 0
@@ -336,8 +1215,30 @@ func (c *Context) header(label string, istreenode bool, opt Option) Response {
 	}
 
 	return c.Control(id, 0, func(r image.Rectangle) Response {
+		var res Response
+		// handle check box (it takes the hover over the header itself)
+		var box image.Rectangle
+		var boxid ID
+		if state != nil {
+			box = image.Rect(r.Min.X+r.Dy(), r.Min.Y, r.Min.X+2*r.Dy(), r.Max.Y)
+			boxid = fnv1a(id, []byte("!check"))
+			c.updateControl(boxid, box, 0)
+			// Space toggles the check box of a focused node
+			if (c.mousePressed == mouseLeft && c.focus == boxid) || (c.focus == id && c.keyActivated(keySpace)) {
+				if *state == TriChecked {
+					*state = TriUnchecked
+				} else {
+					*state = TriChecked
+				}
+				res |= ResponseChange
+			}
+		}
+
 		// handle click (TODO (port): check if this is correct)
-		clicked := c.mousePressed == mouseLeft && c.focus == id
+		clicked := c.activated(id)
+		if state != nil {
+			clicked = c.focus == id && (c.mousePressed == mouseLeft || c.keyActivated(keyReturn))
+		}
 		v1, v2 := 0, 0
 		if active {
 			v1 = 1
@@ -350,12 +1251,12 @@ func (c *Context) header(label string, istreenode bool, opt Option) Response {
 		// update pool ref
 		if idx >= 0 {
 			if active {
-				c.poolUpdate(c.treeNodePool[:], idx)
+				c.poolUpdate(c.treeNodePool, idx)
 			} else {
 				c.treeNodePool[idx] = poolItem{}
 			}
 		} else if active {
-			c.poolInit(c.treeNodePool[:], id)
+			c.poolInit(c.treeNodePool, id)
 		}
 
 		// draw
@@ -378,21 +1279,26 @@ func (c *Context) header(label string, istreenode bool, opt Option) Response {
 			c.Style.Colors[ColorText],
 		)
 		r.Min.X += r.Dy() - c.Style.Padding
+		if state != nil {
+			c.drawControlFrame(boxid, box, ColorBase, 0)
+			c.drawCheckState(box, *state)
+			r.Min.X = box.Max.X - c.Style.Padding
+		}
 		c.drawControlText(label, r, ColorText, 0)
 
 		if expanded {
-			return ResponseActive
+			res |= ResponseActive
 		}
-		return 0
+		return res
 	})
 }
 
 func (c *Context) HeaderEx(label string, opt Option) Response {
-	return c.header(label, false, opt)
+	return c.header(label, false, nil, opt)
 }
 
 func (c *Context) treeNode(label string, opt Option, f func(res Response)) {
-	res := c.header(label, true, opt)
+	res := c.header(label, true, nil, opt)
 	if res&ResponseActive == 0 {
 		return
 	}
@@ -400,9 +1306,150 @@ func (c *Context) treeNode(label string, opt Option, f func(res Response)) {
 	defer func() {
 		c.layout().indent -= c.Style.Indent
 	}()
-	c.idStack = append(c.idStack, c.LastID)
+	id := c.LastID
+	c.idStack = append(c.idStack, id)
 	defer c.popID()
 	f(res)
+	delete(c.treeNodeRequests, id)
+}
+
+// treeNodeCheck is a tree node with a check box. Checking or unchecking a node
+// is propagated down to the nodes built in f, and the states of these nodes
+// are propagated back up, making the node mixed if they differ. The nodes of a
+// collapsed node aren't built, so they adopt its state once it is expanded.
+func (c *Context) treeNodeCheck(label string, state *TriState, opt Option, f func(res Response)) Response {
+	// adopt the state of a parent that was toggled this frame
+	var res Response
+	if len(c.treeCheckStack) > 0 {
+		if p := c.treeCheckStack[len(c.treeCheckStack)-1].force; p != nil && *state != *p {
+			*state = *p
+			res |= ResponseChange
+		}
+	}
+
+	res |= c.header(label, true, state, opt)
+	id := c.LastID
+	_, pending := c.treeCheckPending[id]
+	if res&ResponseActive == 0 && res&ResponseChange != 0 && !pending {
+		if c.treeCheckPending == nil {
+			c.treeCheckPending = map[ID]struct{}{}
+		}
+		c.treeCheckPending[id] = struct{}{}
+	}
+	if res&ResponseActive != 0 {
+		var tc treeCheck
+		if res&ResponseChange != 0 || pending {
+			tc.force = state
+			delete(c.treeCheckPending, id)
+		}
+		c.treeCheckStack = append(c.treeCheckStack, tc)
+		c.layout().indent += c.Style.Indent
+		c.idStack = append(c.idStack, id)
+		f(res)
+		delete(c.treeNodeRequests, id)
+		c.popID()
+		c.layout().indent -= c.Style.Indent
+		tc = c.treeCheckStack[len(c.treeCheckStack)-1]
+		c.treeCheckStack = c.treeCheckStack[:len(c.treeCheckStack)-1]
+
+		// derive the state from the children
+		last := *state
+		switch {
+		case tc.mixed > 0 || (tc.checked > 0 && tc.unchecked > 0):
+			*state = TriMixed
+		case tc.checked > 0:
+			*state = TriChecked
+		case tc.unchecked > 0:
+			*state = TriUnchecked
+		}
+		if *state != last {
+			res |= ResponseChange
+		}
+	}
+
+	// report the state to the parent
+	if len(c.treeCheckStack) > 0 {
+		tc := &c.treeCheckStack[len(c.treeCheckStack)-1]
+		switch *state {
+		case TriChecked:
+			tc.checked++
+		case TriUnchecked:
+			tc.unchecked++
+		case TriMixed:
+			tc.mixed++
+		}
+	}
+	return res
+}
+
+func (t *TreeItem) matches(query string) bool {
+	if indexFold(t.Label, query) >= 0 {
+		return true
+	}
+	for _, child := range t.Children {
+		if child.matches(query) {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterTree builds a tree of items, showing only the items whose labels
+// contain query, with their ancestors and their descendants. When query
+// changes, the ancestors of matching items are expanded, and they can still be
+// collapsed afterwards. The matches are highlighted. FilterTree returns the
+// leaf item clicked this frame, or nil.
+func (c *Context) FilterTree(items []*TreeItem, query string) *TreeItem {
+	last := WidgetState[string](c.Widget(), c.id([]byte("!filter")))
+	expand := query != *last
+	*last = query
+	return c.filterTree(items, query, expand, false)
+}
+
+// filterTree builds items for FilterTree, expanding the ancestors of the
+// matching items if expand is true. All the items are shown if all is true,
+// like the descendants of a matching item.
+func (c *Context) filterTree(items []*TreeItem, query string, expand, all bool) *TreeItem {
+	var clicked *TreeItem
+	c.SetLayoutRow([]int{-1}, 0)
+	for _, item := range items {
+		if query != "" && !all && !item.matches(query) {
+			continue
+		}
+		c.textHighlight = query
+		if len(item.Children) == 0 {
+			id := c.id([]byte(item.Label))
+			c.Control(id, 0, func(r image.Rectangle) Response {
+				if c.activated(id) {
+					clicked = item
+				}
+				if c.hover == id {
+					c.drawFrame(r, ColorButtonHover)
+				}
+				c.drawControlText(item.Label, r, ColorText, 0)
+				return 0
+			})
+			c.textHighlight = ""
+			continue
+		}
+		if query != "" && expand {
+			for _, child := range item.Children {
+				if child.matches(query) {
+					c.SetTreeNodeExpanded(c.TreeNodeID(item.Label), true)
+					break
+				}
+			}
+		}
+		matched := query != "" && indexFold(item.Label, query) >= 0
+		c.treeNode(item.Label, 0, func(res Response) {
+			c.textHighlight = ""
+			if item := c.filterTree(item.Children, query, expand, all || matched); item != nil {
+				clicked = item
+			}
+		})
+		c.textHighlight = ""
+	}
+	return clicked
 }
 
 // x = x, y = y, w = w, h = h
@@ -414,12 +1461,31 @@ func (c *Context) scrollbarVertical(cnt *Container, b image.Rectangle, cs image.
 		// get sizing / positioning
 		base := b
 		base.Min.X = b.Max.X
-		base.Max.X = base.Min.X + c.Style.ScrollbarSize
+		base.Max.X = base.Min.X + c.containerStyle(cnt).ScrollbarSize
 
-		// handle input
+		// handle input: dragging the thumb, paging on track clicks, and
+		// panning with the middle mouse button
 		c.updateControl(id, base, 0)
-		if c.focus == id && c.mouseDown == mouseLeft {
-			cnt.Scroll.Y += c.mouseDelta.Y * cs.Y / base.Dy()
+		thumbsize := max(c.Style.ThumbSize, base.Dy()*b.Dy()/cs.Y)
+		thumbpos := base.Min.Y + cnt.Scroll.Y*(base.Dy()-thumbsize)/maxscroll
+		if c.focus == id && c.mousePressed == mouseLeft {
+			c.scrollGrabbed = false
+			if c.mousePos.Y < thumbpos {
+				cnt.Scroll.Y -= b.Dy()
+			} else if c.mousePos.Y >= thumbpos+thumbsize {
+				cnt.Scroll.Y += b.Dy()
+			} else {
+				c.scrollGrab = c.mousePos.Y - thumbpos
+				c.scrollGrabbed = true
+			}
+		} else if c.focus == id && c.mouseDown == mouseLeft && c.scrollGrabbed {
+			// keep the thumb under the cursor where it was grabbed
+			if track := base.Dy() - thumbsize; track > 0 {
+				cnt.Scroll.Y = (c.mousePos.Y - c.scrollGrab - base.Min.Y) * maxscroll / track
+			}
+		}
+		if c.panTarget == cnt && (c.mouseDown&mouseMiddle) != 0 {
+			cnt.Scroll.Y -= c.mouseDelta.Y
 		}
 		// clamp scroll to limits
 		cnt.Scroll.Y = clamp(cnt.Scroll.Y, 0, maxscroll)
@@ -427,7 +1493,7 @@ func (c *Context) scrollbarVertical(cnt *Container, b image.Rectangle, cs image.
 		// draw base and thumb
 		c.drawFrame(base, ColorScrollBase)
 		thumb := base
-		thumb.Max.Y = thumb.Min.Y + max(c.Style.ThumbSize, base.Dy()*b.Dy()/cs.Y)
+		thumb.Max.Y = thumb.Min.Y + thumbsize
 		thumb = thumb.Add(image.Pt(0, cnt.Scroll.Y*(base.Dy()-thumb.Dy())/maxscroll))
 		c.drawFrame(thumb, ColorScrollThumb)
 
@@ -435,6 +1501,9 @@ func (c *Context) scrollbarVertical(cnt *Container, b image.Rectangle, cs image.
 		// if the mouse is over it
 		if c.mouseOver(b) {
 			c.scrollTarget = cnt
+			if c.mousePressed == mouseMiddle {
+				c.panTarget = cnt
+			}
 		}
 	} else {
 		cnt.Scroll.Y = 0
@@ -450,12 +1519,31 @@ func (c *Context) scrollbarHorizontal(cnt *Container, b image.Rectangle, cs imag
 		// get sizing / positioning
 		base := b
 		base.Min.Y = b.Max.Y
-		base.Max.Y = base.Min.Y + c.Style.ScrollbarSize
+		base.Max.Y = base.Min.Y + c.containerStyle(cnt).ScrollbarSize
 
-		// handle input
+		// handle input: dragging the thumb, paging on track clicks, and
+		// panning with the middle mouse button
 		c.updateControl(id, base, 0)
-		if c.focus == id && c.mouseDown == mouseLeft {
-			cnt.Scroll.X += c.mouseDelta.X * cs.X / base.Dx()
+		thumbsize := max(c.Style.ThumbSize, base.Dx()*b.Dx()/cs.X)
+		thumbpos := base.Min.X + cnt.Scroll.X*(base.Dx()-thumbsize)/maxscroll
+		if c.focus == id && c.mousePressed == mouseLeft {
+			c.scrollGrabbed = false
+			if c.mousePos.X < thumbpos {
+				cnt.Scroll.X -= b.Dx()
+			} else if c.mousePos.X >= thumbpos+thumbsize {
+				cnt.Scroll.X += b.Dx()
+			} else {
+				c.scrollGrab = c.mousePos.X - thumbpos
+				c.scrollGrabbed = true
+			}
+		} else if c.focus == id && c.mouseDown == mouseLeft && c.scrollGrabbed {
+			// keep the thumb under the cursor where it was grabbed
+			if track := base.Dx() - thumbsize; track > 0 {
+				cnt.Scroll.X = (c.mousePos.X - c.scrollGrab - base.Min.X) * maxscroll / track
+			}
+		}
+		if c.panTarget == cnt && (c.mouseDown&mouseMiddle) != 0 {
+			cnt.Scroll.X -= c.mouseDelta.X
 		}
 		// clamp scroll to limits
 		cnt.Scroll.X = clamp(cnt.Scroll.X, 0, maxscroll)
@@ -463,7 +1551,7 @@ func (c *Context) scrollbarHorizontal(cnt *Container, b image.Rectangle, cs imag
 		// draw base and thumb
 		c.drawFrame(base, ColorScrollBase)
 		thumb := base
-		thumb.Max.X = thumb.Min.X + max(c.Style.ThumbSize, base.Dx()*b.Dx()/cs.X)
+		thumb.Max.X = thumb.Min.X + thumbsize
 		thumb = thumb.Add(image.Pt(cnt.Scroll.X*(base.Dx()-thumb.Dx())/maxscroll, 0))
 		c.drawFrame(thumb, ColorScrollThumb)
 
@@ -471,6 +1559,9 @@ func (c *Context) scrollbarHorizontal(cnt *Container, b image.Rectangle, cs imag
 		// if the mouse is over it
 		if c.mouseOver(b) {
 			c.scrollTarget = cnt
+			if c.mousePressed == mouseMiddle {
+				c.panTarget = cnt
+			}
 		}
 	} else {
 		cnt.Scroll.X = 0
@@ -487,10 +1578,11 @@ func (c *Context) scrollbar(cnt *Container, b image.Rectangle, cs image.Point, s
 }
 
 func (c *Context) scrollbars(cnt *Container, body image.Rectangle) image.Rectangle {
-	sz := c.Style.ScrollbarSize
+	style := c.containerStyle(cnt)
+	sz := style.ScrollbarSize
 	cs := cnt.ContentSize
-	cs.X += c.Style.Padding * 2
-	cs.Y += c.Style.Padding * 2
+	cs.X += style.Padding * 2
+	cs.Y += style.Padding * 2
 	c.pushClipRect(body)
 	// resize body to make room for scrollbars
 	if cs.Y > cnt.Body.Dy() {
@@ -507,11 +1599,34 @@ func (c *Context) scrollbars(cnt *Container, body image.Rectangle) image.Rectang
 	return body
 }
 
+// alignContent returns the area of the layout of the container cnt within r,
+// centering the content with OptContentCenter or aligning it to the bottom
+// with OptContentBottom when it is smaller than r, as measured in the last
+// frame.
+func alignContent(cnt *Container, r image.Rectangle, opt Option) image.Rectangle {
+	free := r.Size().Sub(cnt.ContentSize)
+	switch {
+	case (opt & OptContentCenter) != 0:
+		if free.X > 0 {
+			r.Min.X += free.X / 2
+			r.Max.X -= free.X / 2
+		}
+		if free.Y > 0 {
+			r.Min.Y += free.Y / 2
+		}
+	case (opt & OptContentBottom) != 0:
+		if free.Y > 0 {
+			r.Min.Y += free.Y
+		}
+	}
+	return r
+}
+
 func (c *Context) pushContainerBody(cnt *Container, body image.Rectangle, opt Option) {
 	if (^opt & OptNoScroll) != 0 {
 		body = c.scrollbars(cnt, body)
 	}
-	c.pushLayout(body.Inset(c.Style.Padding), cnt.Scroll)
+	c.pushLayout(alignContent(cnt, body.Inset(c.containerStyle(cnt).Padding), opt), cnt.Scroll)
 	cnt.Body = body
 }
 
@@ -519,9 +1634,32 @@ func (c *Context) window(title string, rect image.Rectangle, opt Option, f func(
 	id := c.id([]byte(title))
 
 	cnt := c.container(id, opt)
-	if cnt == nil || !cnt.Open {
+	if cnt == nil {
+		return
+	}
+	cnt.name = title
+	cnt.id = id
+	cnt.titleRect = image.Rectangle{}
+	// a closing window is still built until the end of its transition,
+	// without interaction
+	t, closing := c.windowTransition(cnt, opt)
+	if (!cnt.Open || cnt.Minimized) && !closing {
 		return
 	}
+	// only the window of the selected tab of a group of tabbed windows is
+	// built, and the windows of the group share its frame
+	tabs := windowTabs(cnt)
+	if activeWindowTab(cnt) != cnt {
+		return
+	}
+	defer func() {
+		for _, m := range windowTabs(cnt) {
+			m.Rect = cnt.Rect
+		}
+	}()
+	if c.profiling {
+		defer c.profileStart(title, id)()
+	}
 	c.idStack = append(c.idStack, id)
 	defer c.popID()
 	// This is popped at endRootContainer.
@@ -530,6 +1668,12 @@ func (c *Context) window(title string, rect image.Rectangle, opt Option, f func(
 	if cnt.Rect.Dx() == 0 {
 		cnt.Rect = rect
 	}
+	if cnt.popup {
+		c.placePopup(cnt)
+	}
+	if c.outsideScreen(cnt, opt) {
+		return
+	}
 
 	c.containerStack = append(c.containerStack, cnt)
 	defer c.popContainer()
@@ -537,6 +1681,20 @@ func (c *Context) window(title string, rect image.Rectangle, opt Option, f func(
 	// push container to roots list and push head command
 	c.rootList = append(c.rootList, cnt)
 	cnt.HeadIdx = c.pushJump(-1)
+	local := localTransform(cnt)
+	c.pushTransform(local)
+	defer c.applyTransition(cnt, local, t)()
+	start, roots := len(c.commandList), len(c.rootList)
+
+	// the mouse of an offscreen window or a window of a viewport is in their
+	// own space
+	if pos, delta, ok := c.windowCursor(cnt); ok {
+		mousePos, mouseDelta := c.mousePos, c.mouseDelta
+		defer func() {
+			c.mousePos, c.mouseDelta = mousePos, mouseDelta
+		}()
+		c.mousePos, c.mouseDelta = pos, delta
+	}
 	defer func() {
 		// push tail 'goto' jump command and set head 'skip' command. the final steps
 		// on initing these are done in End
@@ -546,10 +1704,33 @@ func (c *Context) window(title string, rect image.Rectangle, opt Option, f func(
 	}()
 
 	// set as hover root if the mouse is overlapping this container and it has a
-	// higher zindex than the current hover root
-	if c.mousePos.In(cnt.Rect) && (c.nextHoverRoot == nil || cnt.ZIndex > c.nextHoverRoot.ZIndex) {
+	// higher layer or zindex than the current hover root
+	// while a modal window is open, only it and the windows in front of it can
+	// be hovered; a window with OptInputPassthrough is never hovered, so that
+	// the input goes to what is under it
+	if (opt&OptInputPassthrough) == 0 && !closing && c.mousePos.In(local.rect(cnt.Rect)) &&
+		(c.nextHoverRoot == nil || behind(c.nextHoverRoot, cnt)) &&
+		(c.modal == nil || cnt == c.modal || behind(c.modal, cnt)) {
 		c.nextHoverRoot = cnt
 	}
+	if (opt&OptModal) != 0 && !closing {
+		c.nextModal = cnt
+		if cnt.Layer < LayerModal {
+			cnt.Layer = LayerModal
+		}
+	}
+
+	// the mouse is in the scaled space of the window while it is built, but
+	// the window is moved in the UI space
+	delta := c.mouseDelta
+	if local != (transform{}) {
+		mousePos, mouseDelta := c.mousePos, c.mouseDelta
+		defer func() {
+			c.mousePos, c.mouseDelta = mousePos, mouseDelta
+		}()
+		c.mousePos = image.Pt(local.invert(mousePos.X, mousePos.Y))
+		c.mouseDelta = c.mousePos.Sub(image.Pt(local.invert(mousePos.X-mouseDelta.X, mousePos.Y-mouseDelta.Y)))
+	}
 
 	// clipping is reset here in case a root-container is made within
 	// another root-containers's begin/end block; this prevents the inner
@@ -557,27 +1738,52 @@ func (c *Context) window(title string, rect image.Rectangle, opt Option, f func(
 	c.clipStack = append(c.clipStack, unclippedRect)
 	defer c.popClipRect()
 
+	// with OptDim, everything behind the window is dimmed
+	if (opt & OptDim) != 0 {
+		c.drawRect(unclippedRect, c.Style.Colors[ColorDim])
+	}
+
 	body := cnt.Rect
 	rect = body
+	movable := (opt & OptNoMove) == 0
 
 	// draw frame
 	if (^opt & OptNoFrame) != 0 {
 		c.drawFrame(rect, ColorWindowBG)
 	}
 
+	// do the drag region first, under all the other controls of the window,
+	// like its buttons, scrollbars and content, which are updated after it
+	// and take the hover from it
+	if dr, ok := dragRect(cnt); ok && movable {
+		id := c.id([]byte("!drag"))
+		c.updateControl(id, dr, opt)
+		if id == c.focus && c.mouseDown == mouseLeft {
+			cnt.Rect = cnt.Rect.Add(delta)
+		}
+	}
+
 	// do title bar
 	if (^opt & OptNoTitle) != 0 {
 		tr := rect
-		tr.Max.Y = tr.Min.Y + c.Style.TitleHeight
+		tr.Max.Y = tr.Min.Y + c.containerStyle(cnt).TitleHeight
 		c.drawFrame(tr, ColorTitleBG)
+		if !cnt.Offscreen && cnt.Viewport == 0 {
+			cnt.titleRect = local.rect(tr)
+		}
 
-		// do title text
+		// do title text, or the tabs of the tabbed windows
 		if (^opt & OptNoTitle) != 0 {
 			id := c.id([]byte("!title"))
 			c.updateControl(id, tr, opt)
-			c.drawControlText(title, tr, ColorTitleText, opt)
-			if id == c.focus && c.mouseDown == mouseLeft {
-				cnt.Rect = cnt.Rect.Add(c.mouseDelta)
+			if len(tabs) > 1 {
+				c.windowTabBar(cnt, tabs, tr, delta, movable)
+			} else {
+				c.drawControlText(title, tr, ColorTitleText, opt)
+			}
+			if id == c.focus && c.mouseDown == mouseLeft && movable {
+				cnt.Rect = cnt.Rect.Add(delta)
+				c.dragWindowTitle(cnt)
 			}
 			body.Min.Y += tr.Dy()
 		}
@@ -590,7 +1796,24 @@ func (c *Context) window(title string, rect image.Rectangle, opt Option, f func(
 			c.drawIcon(iconClose, r, c.Style.Colors[ColorTitleText])
 			c.updateControl(id, r, opt)
 			if c.mousePressed == mouseLeft && id == c.focus {
-				cnt.Open = false
+				if cnt.CloseHandler == nil || cnt.CloseHandler() {
+					cnt.Open = false
+				}
+			}
+		}
+
+		// do `minimize` button
+		if (opt & OptMinimizable) != 0 {
+			id := c.id([]byte("!minimize"))
+			r := image.Rect(tr.Max.X-tr.Dy(), tr.Min.Y, tr.Max.X, tr.Max.Y)
+			tr.Max.X -= r.Dx()
+			m := r.Inset(r.Dx() / 3)
+			m.Min.Y = m.Max.Y - 2
+			c.drawRect(m, c.Style.Colors[ColorTitleText])
+			c.updateControl(id, r, opt)
+			if c.mousePressed == mouseLeft && id == c.focus {
+				cnt.Minimized = true
+				cnt.minimizedAt = c.tick
 			}
 		}
 	}
@@ -625,8 +1848,20 @@ func (c *Context) window(title string, rect image.Rectangle, opt Option, f func(
 	defer c.popClipRect()
 
 	f(ResponseActive)
+
+	// a popup is hidden the first frame it is open, while its content is
+	// measured, so that it is shown at its size and place right away
+	if cnt.measuring {
+		cnt.measuring = false
+		if len(c.rootList) == roots {
+			c.commandList = c.commandList[:start]
+		}
+	}
 }
 
+// OpenPopup opens the popup named name at the mouse cursor, or below the
+// focused control if it was focused with the keyboard in keyboard navigation
+// mode. The items of the popup can then be focused with the Up and Down keys.
 func (c *Context) OpenPopup(name string) {
 	cnt := c.Container(name)
 	// set as hover root so popup isn't closed in begin_window_ex()
@@ -634,19 +1869,78 @@ func (c *Context) OpenPopup(name string) {
 	c.hoverRoot = c.nextHoverRoot
 	// position at mouse cursor, open and bring-to-front
 	cnt.Rect = image.Rect(c.mousePos.X, c.mousePos.Y, c.mousePos.X+1, c.mousePos.Y+1)
+	cnt.popup = true
+	cnt.popupAnchor = image.Rectangle{Min: c.mousePos, Max: c.mousePos}
+	if c.KeyboardNavigation && c.focusByKey && c.focus != 0 {
+		// focusRing is the area of the control focused with the keyboard
+		a := c.focusRing
+		cnt.Rect = image.Rect(a.Min.X, a.Max.Y, a.Min.X+1, a.Max.Y+1)
+		cnt.popupAnchor = a
+	}
+	cnt.measuring = true
 	cnt.Open = true
+	cnt.Layer = c.popupLayer(c.rootContainer())
+	inheritTarget(cnt, c.rootContainer())
 	c.bringToFront(cnt)
 }
 
+// outsideScreen reports whether the window cnt is entirely out of the screen,
+// like a window dragged beyond its edges, so that it is neither built nor
+// drawn. The modal windows are always built.
+func (c *Context) outsideScreen(cnt *Container, opt Option) bool {
+	if c.screenRect.Empty() || cnt.Offscreen || cnt.Viewport != 0 || (opt&OptModal) != 0 {
+		return false
+	}
+	r := localTransform(cnt).rect(cnt.Rect)
+	return !r.Empty() && !r.Overlaps(c.screenRect)
+}
+
+// placePopup places the auto-sized popup cnt below its anchor, or above it if
+// there is no room below, and moves it left if needed to keep it on the
+// screen.
+func (c *Context) placePopup(cnt *Container) {
+	a := cnt.popupAnchor
+	p := c.containerStyle(cnt).Padding
+	size := cnt.ContentSize.Add(image.Pt(p*2, p*2))
+	size.X = max(size.X, a.Dx())
+	r := image.Rectangle{Min: image.Pt(a.Min.X, a.Max.Y), Max: image.Pt(a.Min.X+size.X, a.Max.Y+size.Y)}
+	screen := c.screenRect
+	if screen.Empty() || cnt.Offscreen || cnt.Viewport != 0 {
+		cnt.Rect = r
+		return
+	}
+	if r.Max.Y > screen.Max.Y && a.Min.Y-size.Y >= screen.Min.Y {
+		r = r.Sub(image.Pt(0, r.Min.Y-(a.Min.Y-size.Y)))
+	}
+	if r.Max.X > screen.Max.X {
+		r = r.Sub(image.Pt(r.Max.X-min(a.Max.X, screen.Max.X), 0))
+	}
+	// clamp to the screen, keeping the top-left corner visible
+	r = r.Sub(image.Pt(max(r.Max.X-screen.Max.X, 0), max(r.Max.Y-screen.Max.Y, 0)))
+	r = r.Add(image.Pt(max(screen.Min.X-r.Min.X, 0), max(screen.Min.Y-r.Min.Y, 0)))
+	cnt.Rect = r
+}
+
 func (c *Context) Popup(name string, f func(res Response)) {
-	opt := OptPopup | OptAutoSize | OptNoResize | OptNoScroll | OptNoTitle | OptClosed
+	c.PopupEx(name, 0, f)
+}
+
+// PopupEx is like Popup with the additional options opt, like OptDim to dim
+// everything behind the popup.
+func (c *Context) PopupEx(name string, opt Option, f func(res Response)) {
+	opt |= OptPopup | OptAutoSize | OptNoResize | OptNoScroll | OptNoTitle | OptClosed
 	c.window(name, image.Rectangle{}, opt, f)
 }
 
 func (c *Context) panel(name string, opt Option, f func()) {
 	id := c.pushID([]byte(name))
 	defer c.popID()
+	c.panelWithID(id, opt, f)
+}
 
+// panelWithID builds a panel whose container is identified by id, which must
+// already be pushed to the ID stack.
+func (c *Context) panelWithID(id ID, opt Option, f func()) {
 	cnt := c.container(id, opt)
 	cnt.Rect = c.layoutNext()
 	if (^opt & OptNoFrame) != 0 {
@@ -662,3 +1956,131 @@ func (c *Context) panel(name string, opt Option, f func()) {
 
 	f()
 }
+
+// anchoredPopup builds a popup window right below the anchor rect, as wide as
+// the anchor rect.
+func (c *Context) anchoredPopup(name string, anchor image.Rectangle, f func()) {
+	cnt := c.Container(name)
+	if !cnt.Open {
+		cnt.measuring = true
+	}
+	cnt.Rect = image.Rect(anchor.Min.X, anchor.Max.Y, anchor.Max.X, anchor.Max.Y+max(cnt.Rect.Dy(), 1))
+	cnt.popup = true
+	cnt.popupAnchor = anchor
+	cnt.Open = true
+	cnt.Layer = c.popupLayer(c.rootContainer())
+	inheritTarget(cnt, c.rootContainer())
+	if cnt.ZIndex < c.lastZIndex {
+		c.bringToFront(cnt)
+	}
+	opt := OptAutoSize | OptNoResize | OptNoScroll | OptNoTitle
+	c.window(name, cnt.Rect, opt, func(res Response) {
+		f()
+	})
+}
+
+// TextBoxWithSuggestions is a text box showing the suggestions returned by
+// suggest for its text in a popup below it. The suggestions can be selected
+// with the up and down keys, and accepted with Tab or Enter, or by clicking
+// them.
+func (c *Context) TextBoxWithSuggestions(buf *string, suggest func(string) []string) Response {
+	id := c.id(ptrToBytes(unsafe.Pointer(buf)))
+	if c.focus != id {
+		if c.suggest == id {
+			c.suggest = 0
+		}
+		return c.textBoxRaw(buf, id, textBoxConfig{}, 0)
+	}
+	if c.suggest != id {
+		c.suggest = id
+		c.suggestIndex = -1
+		c.suggestHidden = false
+	}
+
+	var suggestions []string
+	if !c.suggestHidden {
+		suggestions = suggest(*buf)
+	}
+
+	// an accepted suggestion is reported as a change of the text box, once
+	// the popup is built
+	var res Response
+	var last string
+	var accepted bool
+	accept := func(s string) {
+		if !accepted {
+			last = *buf
+		}
+		*buf = s
+		c.textCaret, c.textAnchor = len(s), len(s)
+		res |= ResponseChange
+		c.suggestHidden = true
+		accepted = true
+	}
+	defer func() {
+		if accepted && *buf != last {
+			c.eventValues(last, *buf)
+			c.emitEvents(id, ResponseChange)
+		}
+	}()
+
+	// handle navigation and acceptance before the text box handles the keys
+	if len(suggestions) > 0 {
+		if (c.keyPressed & keyDown) != 0 {
+			c.suggestIndex++
+		}
+		if (c.keyPressed & keyUp) != 0 {
+			c.suggestIndex--
+		}
+		c.keyPressed &^= keyUp | keyDown
+		c.suggestIndex = clamp(c.suggestIndex, -1, len(suggestions)-1)
+		if (c.keyPressed&keyTab) != 0 || ((c.keyPressed&keyReturn) != 0 && c.suggestIndex >= 0) {
+			accept(suggestions[max(c.suggestIndex, 0)])
+			c.keyPressed &^= keyReturn | keyTab
+		}
+		if (c.keyPressed & keyEscape) != 0 {
+			// only hide the suggestions, without canceling the edit
+			c.suggestHidden = true
+			c.keyPressed &^= keyEscape
+		}
+	}
+
+	textRes := c.textBoxRaw(buf, id, textBoxConfig{}, 0)
+	if (textRes & ResponseChange) != 0 {
+		c.suggestIndex = -1
+		c.suggestHidden = false
+	}
+	res |= textRes
+
+	if len(suggestions) == 0 || c.suggestHidden {
+		return res
+	}
+	c.anchoredPopup("!suggestions", c.lastRect, func() {
+		c.SetLayoutRow([]int{-1}, 0)
+		for i, s := range suggestions {
+			sid := c.id([]byte(s))
+			c.Control(sid, 0, func(r image.Rectangle) Response {
+				if c.activated(sid) {
+					accept(s)
+					c.SetFocus(id)
+				}
+				if i == c.suggestIndex {
+					c.drawFrame(r, ColorHighlight)
+				} else if c.hover == sid {
+					c.drawFrame(r, ColorButtonHover)
+				}
+				c.drawControlText(s, r, ColorText, 0)
+				return 0
+			})
+		}
+	})
+	return res
+}
+
+// AutoComplete is a text box completing its text with the completions
+// returned by suggestions for the text typed so far, shown in a popup list
+// under the box. The completions are selected with the up and down keys, and
+// accepted with Tab or Enter, like in TextBoxWithSuggestions.
+func (c *Context) AutoComplete(buf *string, suggestions func(prefix string) []string) Response {
+	return c.TextBoxWithSuggestions(buf, suggestions)
+}
@@ -0,0 +1,293 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 The Ebitengine Authors
+
+package microui
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"slices"
+	"unsafe"
+)
+
+// CurvePoint is a control point of a curve edited with CurveEditor.
+type CurvePoint struct {
+	X, Y float64
+	// In and Out are the slopes of the curve arriving to and leaving the
+	// point.
+	In, Out float64
+}
+
+// EvalCurve returns the value at x of the curve going through points, sorted
+// by X, with cubic Hermite segments between the points. The curve is
+// constant before the first point and after the last one.
+func EvalCurve(points []CurvePoint, x float64) float64 {
+	if len(points) == 0 {
+		return 0
+	}
+	if x <= points[0].X {
+		return points[0].Y
+	}
+	for i := 1; i < len(points); i++ {
+		p0, p1 := points[i-1], points[i]
+		if x > p1.X {
+			continue
+		}
+		h := p1.X - p0.X
+		if h <= 0 {
+			return p1.Y
+		}
+		t := (x - p0.X) / h
+		t2, t3 := t*t, t*t*t
+		return (2*t3-3*t2+1)*p0.Y + (t3-2*t2+t)*h*p0.Out + (-2*t3+3*t2)*p1.Y + (t3-t2)*h*p1.In
+	}
+	return points[len(points)-1].Y
+}
+
+// curveView is the state of a curve editor.
+type curveView struct {
+	init       bool
+	minX, maxX float64
+	minY, maxY float64
+	selected   int
+	// dragging is the dragged point, or -1; handle is -1 or 1 while a
+	// tangent handle of the selected point is dragged.
+	dragging int
+	handle   int
+}
+
+const (
+	curvePointSize  = 5
+	curveHandleSize = 30
+)
+
+// toScreen returns the position in r of the point (x, y) of the curve.
+func (v *curveView) toScreen(r image.Rectangle, x, y float64) (float64, float64) {
+	sx := float64(r.Min.X) + (x-v.minX)/(v.maxX-v.minX)*float64(r.Dx())
+	sy := float64(r.Max.Y) - (y-v.minY)/(v.maxY-v.minY)*float64(r.Dy())
+	return sx, sy
+}
+
+// fromScreen returns the point of the curve at the position p in r.
+func (v *curveView) fromScreen(r image.Rectangle, p image.Point) (float64, float64) {
+	x := v.minX + float64(p.X-r.Min.X)/float64(r.Dx())*(v.maxX-v.minX)
+	y := v.minY + float64(r.Max.Y-p.Y)/float64(r.Dy())*(v.maxY-v.minY)
+	return x, y
+}
+
+// handlePos returns the position of the tangent handle dir (-1 for In, 1 for
+// Out) of the point p.
+func (v *curveView) handlePos(r image.Rectangle, p CurvePoint, dir int) image.Point {
+	slope := p.Out
+	if dir < 0 {
+		slope = p.In
+	}
+	// the slope in the screen space
+	s := -slope * (v.maxX - v.minX) / float64(r.Dx()) * float64(r.Dy()) / (v.maxY - v.minY)
+	d := curveHandleSize / math.Hypot(1, s)
+	x, y := v.toScreen(r, p.X, p.Y)
+	return image.Pt(int(x+float64(dir)*d), int(y+float64(dir)*d*s))
+}
+
+// CurveEditor edits the curve going through points, as evaluated by
+// EvalCurve, in a control of the given height filling the width of the next
+// cell of the layout:
+//
+//   - the points are moved by dragging them, and the tangents of the
+//     selected point by dragging its handles, both together unless Alt is
+//     held;
+//   - a double click adds a point, and a right click removes one;
+//   - the mouse wheel zooms the view, and dragging with the middle mouse
+//     button pans it.
+func (c *Context) CurveEditor(points *[]CurvePoint, height int) Response {
+	id := c.id(ptrToBytes(unsafe.Pointer(points)))
+	v := WidgetState[curveView](c.Widget(), id)
+	if !v.init {
+		*v = curveView{init: true, maxX: 1, maxY: 1, selected: -1, dragging: -1}
+	}
+
+	var res Response
+	c.LayoutColumn(func() {
+		c.SetLayoutRow([]int{-1}, height)
+		res = c.curveEditor(points, id, v)
+	})
+	return res
+}
+
+func (c *Context) curveEditor(points *[]CurvePoint, id ID, v *curveView) Response {
+	return c.Control(id, OptHoldFocus, func(r image.Rectangle) Response {
+		var res Response
+		pts := *points
+		mx, my := v.fromScreen(r, c.mousePos)
+
+		// handle the view
+		if c.mouseOver(r) && c.scrollDelta.Y != 0 {
+			f := math.Pow(1.1, float64(c.scrollDelta.Y)/30)
+			v.minX = mx + (v.minX-mx)*f
+			v.maxX = mx + (v.maxX-mx)*f
+			v.minY = my + (v.minY-my)*f
+			v.maxY = my + (v.maxY-my)*f
+			// consume the wheel so that it doesn't scroll the container
+			c.scrollDelta = image.Point{}
+		}
+		if c.focus == id && (c.mouseDown&mouseMiddle) != 0 {
+			dx := float64(c.mouseDelta.X) / float64(r.Dx()) * (v.maxX - v.minX)
+			dy := float64(c.mouseDelta.Y) / float64(r.Dy()) * (v.maxY - v.minY)
+			v.minX, v.maxX = v.minX-dx, v.maxX-dx
+			v.minY, v.maxY = v.minY+dy, v.maxY+dy
+		}
+
+		// handle the points
+		if v.selected >= len(pts) {
+			v.selected = -1
+		}
+		hit := func(p image.Point) bool {
+			return c.mousePos.Sub(p).In(image.Rect(-curvePointSize, -curvePointSize, curvePointSize+1, curvePointSize+1))
+		}
+		pointAt := func() int {
+			for i, p := range pts {
+				x, y := v.toScreen(r, p.X, p.Y)
+				if hit(image.Pt(int(x), int(y))) {
+					return i
+				}
+			}
+			return -1
+		}
+		if c.focus == id && c.mousePressed == mouseLeft {
+			v.dragging, v.handle = -1, 0
+			if v.selected >= 0 && hit(v.handlePos(r, pts[v.selected], -1)) {
+				v.handle = -1
+			} else if v.selected >= 0 && hit(v.handlePos(r, pts[v.selected], 1)) {
+				v.handle = 1
+			} else if i := pointAt(); i >= 0 {
+				v.selected, v.dragging = i, i
+			} else if c.doubleClicked {
+				i, _ := slices.BinarySearchFunc(pts, mx, func(p CurvePoint, x float64) int {
+					switch {
+					case p.X < x:
+						return -1
+					case p.X > x:
+						return 1
+					}
+					return 0
+				})
+				pts = slices.Insert(pts, i, CurvePoint{X: mx, Y: my})
+				v.selected, v.dragging = i, i
+				res |= ResponseChange
+			} else {
+				v.selected = -1
+			}
+		}
+		if c.focus == id && c.mousePressed == mouseRight {
+			if i := pointAt(); i >= 0 {
+				pts = slices.Delete(pts, i, i+1)
+				v.selected, v.dragging = -1, -1
+				res |= ResponseChange
+			}
+		}
+		if c.focus == id && c.mouseDown == mouseLeft && c.mousePressed == 0 {
+			switch {
+			case v.dragging >= 0:
+				// keep the points sorted
+				lo, hi := math.Inf(-1), math.Inf(1)
+				if v.dragging > 0 {
+					lo = pts[v.dragging-1].X
+				}
+				if v.dragging < len(pts)-1 {
+					hi = pts[v.dragging+1].X
+				}
+				p := &pts[v.dragging]
+				p.X, p.Y = clampF(mx, lo, hi), my
+				res |= ResponseChange
+			case v.handle != 0 && v.selected >= 0:
+				p := &pts[v.selected]
+				if dx := mx - p.X; dx*float64(v.handle) > 0 {
+					slope := (my - p.Y) / dx
+					if v.handle < 0 || (c.keyDown&keyAlt) == 0 {
+						p.In = slope
+					}
+					if v.handle > 0 || (c.keyDown&keyAlt) == 0 {
+						p.Out = slope
+					}
+					res |= ResponseChange
+				}
+			}
+		}
+		if c.mouseDown == 0 {
+			v.dragging, v.handle = -1, 0
+		}
+		*points = pts
+
+		// draw
+		c.drawControlFrame(id, r, ColorBase, 0)
+		c.pushClipRect(r)
+		defer c.popClipRect()
+		c.drawCurveGrid(r, v)
+		c.drawCurve(r, v, pts)
+		for i, p := range pts {
+			x, y := v.toScreen(r, p.X, p.Y)
+			pr := image.Rect(int(x)-curvePointSize/2, int(y)-curvePointSize/2, int(x)+curvePointSize/2+1, int(y)+curvePointSize/2+1)
+			clr := c.Style.Colors[ColorText]
+			if i == v.selected {
+				clr = c.Style.Colors[ColorHighlight]
+				for _, dir := range []int{-1, 1} {
+					h := v.handlePos(r, p, dir)
+					c.drawSegment(image.Pt(int(x), int(y)), h, c.Style.Colors[ColorHighlight])
+					c.drawBox(image.Rect(h.X-2, h.Y-2, h.X+3, h.Y+3), c.Style.Colors[ColorHighlight])
+				}
+			}
+			c.drawRect(pr, clr)
+		}
+		return res
+	})
+}
+
+// drawCurveGrid draws the grid lines of the view v in r, at a power of ten
+// giving a few lines.
+func (c *Context) drawCurveGrid(r image.Rectangle, v *curveView) {
+	clr := c.Style.Colors[ColorBorder]
+	step := func(size float64) float64 {
+		return math.Pow(10, math.Floor(math.Log10(size/2)))
+	}
+	sx := step(v.maxX - v.minX)
+	for x := math.Ceil(v.minX/sx) * sx; x <= v.maxX; x += sx {
+		px, _ := v.toScreen(r, x, 0)
+		c.drawRect(image.Rect(int(px), r.Min.Y, int(px)+1, r.Max.Y), clr)
+	}
+	sy := step(v.maxY - v.minY)
+	for y := math.Ceil(v.minY/sy) * sy; y <= v.maxY; y += sy {
+		_, py := v.toScreen(r, 0, y)
+		c.drawRect(image.Rect(r.Min.X, int(py), r.Max.X, int(py)+1), clr)
+	}
+}
+
+// drawCurve draws the curve of points in r, with a vertical segment per
+// column of pixels.
+func (c *Context) drawCurve(r image.Rectangle, v *curveView, points []CurvePoint) {
+	if len(points) == 0 {
+		return
+	}
+	clr := c.Style.Colors[ColorText]
+	var last int
+	for px := r.Min.X; px < r.Max.X; px++ {
+		x, _ := v.fromScreen(r, image.Pt(px, 0))
+		_, fy := v.toScreen(r, x, EvalCurve(points, x))
+		y := int(fy)
+		if px == r.Min.X {
+			last = y
+		}
+		c.drawRect(image.Rect(px, min(last, y), px+1, max(last, y)+1), clr)
+		last = y
+	}
+}
+
+// drawSegment draws a line from a to b with small rectangles.
+func (c *Context) drawSegment(a, b image.Point, clr color.Color) {
+	n := max(abs(b.X-a.X), abs(b.Y-a.Y))
+	for i := 0; i <= n; i++ {
+		x := a.X + (b.X-a.X)*i/max(n, 1)
+		y := a.Y + (b.Y-a.Y)*i/max(n, 1)
+		c.drawRect(image.Rect(x, y, x+1, y+1), clr)
+	}
+}
@@ -0,0 +1,182 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 The Ebitengine Authors
+
+package microui
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+)
+
+// UINode is a window, a layout or a control of a UI described declaratively,
+// built with BuildUI.
+type UINode struct {
+	// Type is one of "window", "panel", "row", "column", "header", "tree",
+	// "label", "text", "button", "checkbox", "slider", "number" and "textbox".
+	Type string `json:"type"`
+	// Label is the title of a window, the name of a panel, or the text of a
+	// header, a tree node, a label, a text, a button or a check box.
+	Label string `json:"label,omitempty"`
+	// Rect is the x, y, width and height of a window.
+	Rect [4]int `json:"rect,omitempty"`
+	// Widths and Height are the layout of a row, like SetLayoutRow.
+	Widths []int `json:"widths,omitempty"`
+	Height int   `json:"height,omitempty"`
+	// Bind is the name of the value of a control in the UIData: a float for a
+	// slider or a number, a bool for a check box, a string for a text box or
+	// a label, and an action for a button.
+	Bind string `json:"bind,omitempty"`
+	// Min, Max, Step and Format configure a slider or a number.
+	Min    float64 `json:"min,omitempty"`
+	Max    float64 `json:"max,omitempty"`
+	Step   float64 `json:"step,omitempty"`
+	Format string  `json:"format,omitempty"`
+	// Children are the nodes in a window, a panel, a row, a column, a header
+	// or a tree node.
+	Children []UINode `json:"children,omitempty"`
+}
+
+// UIData holds the values and the callbacks a UI built with BuildUI is bound
+// to, by name.
+type UIData struct {
+	Floats  map[string]Binding[float64]
+	Bools   map[string]Binding[bool]
+	Strings map[string]Binding[string]
+	Actions map[string]func()
+}
+
+var uiNodeTypes = map[string]bool{
+	"window": true, "panel": true, "row": true, "column": true, "header": true, "tree": true,
+	"label": true, "text": true, "button": true, "checkbox": true, "slider": true, "number": true, "textbox": true,
+}
+
+// uiBoundTypes are the node types which must have a Bind.
+var uiBoundTypes = map[string]bool{
+	"checkbox": true, "slider": true, "number": true, "textbox": true,
+}
+
+// LoadUI parses a UI described in JSON as an array of nodes, so that tool
+// layouts can be authored without code.
+func LoadUI(data []byte) ([]UINode, error) {
+	var nodes []UINode
+	if err := json.Unmarshal(data, &nodes); err != nil {
+		return nil, fmt.Errorf("microui: parsing UI: %w", err)
+	}
+	if err := checkUINodes(nodes); err != nil {
+		return nil, err
+	}
+	return nodes, nil
+}
+
+func checkUINodes(nodes []UINode) error {
+	for _, n := range nodes {
+		if !uiNodeTypes[n.Type] {
+			return fmt.Errorf("microui: unknown UI node type %q", n.Type)
+		}
+		if uiBoundTypes[n.Type] && n.Bind == "" {
+			return fmt.Errorf("microui: UI node %q of type %q has no bind", n.Label, n.Type)
+		}
+		if err := checkUINodes(n.Children); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BuildUI builds the windows and the controls described by nodes, bound to
+// the values and the callbacks of data. It must be called every frame
+// between Begin and End, like the controls it builds. The controls bound to a
+// name which isn't in data are skipped.
+func (c *Context) BuildUI(nodes []UINode, data *UIData) {
+	if data == nil {
+		data = &UIData{}
+	}
+	for i := range nodes {
+		c.buildUINode(&nodes[i], data)
+	}
+}
+
+func (c *Context) buildUINode(n *UINode, data *UIData) {
+	children := func() {
+		c.BuildUI(n.Children, data)
+	}
+	switch n.Type {
+	case "window":
+		rect := image.Rect(n.Rect[0], n.Rect[1], n.Rect[0]+n.Rect[2], n.Rect[1]+n.Rect[3])
+		c.Window(n.Label, rect, func(Response) {
+			children()
+		})
+	case "panel":
+		c.Panel(n.Label, children)
+	case "row":
+		c.SetLayoutRow(n.Widths, n.Height)
+		children()
+	case "column":
+		c.LayoutColumn(children)
+	case "header":
+		if (c.Header(n.Label) & ResponseActive) != 0 {
+			children()
+		}
+	case "tree":
+		c.TreeNode(n.Label, func(Response) {
+			children()
+		})
+	case "label":
+		text := n.Label
+		if b, ok := uiBinding(data.Strings, n.Bind); ok {
+			text = b.Get()
+		}
+		c.Label(text)
+	case "text":
+		c.Text(n.Label)
+	case "button":
+		if (c.Button(n.Label) & ResponseSubmit) != 0 {
+			if f, ok := uiBinding(data.Actions, n.Bind); ok {
+				f()
+			}
+		}
+	case "checkbox":
+		if b, ok := uiBinding(data.Bools, n.Bind); ok {
+			c.CheckboxBinding(n.Label, b)
+		}
+	case "slider":
+		if b, ok := uiBinding(data.Floats, n.Bind); ok {
+			c.SliderBinding(n.Bind, b, n.Min, n.Max, n.Step, n.format(), OptAlignCenter)
+		}
+	case "number":
+		step := n.Step
+		if step == 0 {
+			step = 1
+		}
+		if b, ok := uiBinding(data.Floats, n.Bind); ok {
+			c.NumberBinding(n.Bind, b, step, n.format(), OptAlignCenter)
+		}
+	case "textbox":
+		if b, ok := uiBinding(data.Strings, n.Bind); ok {
+			c.TextBoxBinding(n.Bind, b, 0)
+		}
+	default:
+		panic(fmt.Sprintf("microui: unknown UI node type %q", n.Type))
+	}
+}
+
+func (n *UINode) format() string {
+	if n.Format == "" {
+		return sliderFmt
+	}
+	return n.Format
+}
+
+// uiBinding returns the value bound to name in m, and whether there is one.
+// A nil action or a binding without accessors isn't bound.
+func uiBinding[T any](m map[string]T, name string) (T, bool) {
+	v, ok := m[name]
+	switch x := any(v).(type) {
+	case func():
+		ok = ok && x != nil
+	case interface{ valid() bool }:
+		ok = ok && x.valid()
+	}
+	return v, ok
+}
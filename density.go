@@ -0,0 +1,50 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 The Ebitengine Authors
+
+package microui
+
+import "math"
+
+// Density is a preset of the spacing of the UI, for a density setting.
+type Density int
+
+const (
+	// DensityNormal is the spacing of the default style.
+	DensityNormal Density = iota
+	// DensityCompact fits more controls, like in editors and debug tools.
+	DensityCompact
+	// DensitySpacious gives more room to the controls, like for touch input.
+	DensitySpacious
+)
+
+func (d Density) scale() float64 {
+	switch d {
+	case DensityCompact:
+		return 0.75
+	case DensitySpacious:
+		return 1.5
+	}
+	return 1
+}
+
+// SetDensity sets the paddings, the spacing and the sizes of the style to the
+// ones of the default style scaled for d. The style is copied first, so that
+// other contexts sharing it aren't changed, and its colors and other settings
+// are kept. The height of the text isn't scaled, so the height of the
+// controls is kept at least as high as the text.
+func (c *Context) SetDensity(d Density) {
+	f := d.scale()
+	scaled := func(v int) int {
+		return max(int(math.Round(float64(v)*f)), 1)
+	}
+	s := *c.Style
+	s.Size.X = scaled(defaultStyle.Size.X)
+	s.Padding = scaled(defaultStyle.Padding)
+	s.Size.Y = max(scaled(defaultStyle.Size.Y), lineHeight()-s.Padding*2)
+	s.Spacing = scaled(defaultStyle.Spacing)
+	s.Indent = scaled(defaultStyle.Indent)
+	s.TitleHeight = scaled(defaultStyle.TitleHeight)
+	s.ScrollbarSize = scaled(defaultStyle.ScrollbarSize)
+	s.ThumbSize = scaled(defaultStyle.ThumbSize)
+	c.Style = &s
+}
@@ -0,0 +1,110 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 The Ebitengine Authors
+
+package microui
+
+import "image"
+
+// dragThreshold is the distance in pixels the mouse has to move while pressed
+// for a drag to start.
+const dragThreshold = 4
+
+// DragSource makes the last control a source of a drag and drop of payload,
+// of the kind payloadType. While dragging, preview is called to build a
+// translucent preview following the cursor, and if dimSource is true, the
+// source control is dimmed.
+func (c *Context) DragSource(payloadType string, payload any, preview func(), dimSource bool) {
+	d := c.drag
+	if d == nil {
+		// start tracking a drag when the control is pressed
+		if c.mousePressed == mouseLeft && c.mouseOver(c.lastRect) {
+			c.drag = &dragState{
+				root:    c.rootContainer(),
+				rect:    c.lastRect,
+				start:   c.mousePos,
+				typ:     payloadType,
+				payload: payload,
+				preview: preview,
+			}
+		}
+		return
+	}
+	if d.root != c.rootContainer() || d.rect != c.lastRect {
+		return
+	}
+	d.typ = payloadType
+	d.payload = payload
+	d.preview = preview
+	if !d.active {
+		p := c.mousePos.Sub(d.start)
+		d.active = p.X*p.X+p.Y*p.Y >= dragThreshold*dragThreshold
+	}
+	if d.active && dimSource {
+		clr := c.Style.Colors[ColorWindowBG]
+		clr.A /= 2
+		clr.R /= 2
+		clr.G /= 2
+		clr.B /= 2
+		c.drawRect(c.lastRect, clr)
+	}
+}
+
+// Dragging returns the kind of the payload being dragged, if any.
+func (c *Context) Dragging() (string, bool) {
+	if c.drag == nil || !c.drag.active {
+		return "", false
+	}
+	return c.drag.typ, true
+}
+
+// DropHover reports whether a payload of the kind payloadType is being dragged
+// over the last control, and returns the cursor position, so that the control
+// can show where the payload would be dropped.
+func (c *Context) DropHover(payloadType string) (image.Point, bool) {
+	d := c.drag
+	if d == nil || !d.active || d.typ != payloadType || !c.mouseOver(c.lastRect) {
+		return image.Point{}, false
+	}
+	return c.mousePos, true
+}
+
+// DropTarget makes the last control a target of drag and drops of the kind
+// payloadType. It returns the payload and true when such a payload is dropped
+// on it.
+func (c *Context) DropTarget(payloadType string) (any, bool) {
+	if _, ok := c.DropHover(payloadType); !ok || (c.mouseReleased&mouseLeft) == 0 {
+		return nil, false
+	}
+	return c.drag.payload, true
+}
+
+func (c *Context) dragPreviewWindow() {
+	d := c.drag
+	if d == nil || !d.active || d.preview == nil {
+		return
+	}
+
+	// position next to the cursor, on top of everything else, where the window
+	// of the source is drawn
+	cnt := c.Container("!drag")
+	inheritTarget(cnt, d.root)
+	pos := c.targetCursor(cnt).Add(image.Pt(12, 12))
+	size := cnt.Rect.Size()
+	if size.X == 0 || size.Y == 0 {
+		size = image.Pt(1, 1)
+	}
+	cnt.Rect = image.Rectangle{Min: pos, Max: pos.Add(size)}
+	cnt.Open = true
+	cnt.Layer = LayerOverlay
+	if cnt.ZIndex < c.lastZIndex {
+		c.bringToFront(cnt)
+	}
+	c.fade = 0.5
+	defer func() {
+		c.fade = 0
+	}()
+	opt := OptAutoSize | OptNoResize | OptNoScroll | OptNoTitle | OptNoInteract
+	c.window("!drag", cnt.Rect, opt, func(res Response) {
+		d.preview()
+	})
+}
@@ -0,0 +1,33 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 The Ebitengine Authors
+
+package microui
+
+import "image"
+
+// DragRegion is the area by which a window is dragged, set with
+// Container.DragRegion.
+type DragRegion int
+
+const (
+	// DragRegionTitle drags the window by its title bar only.
+	DragRegionTitle DragRegion = iota
+	// DragRegionWindow drags the window by any place not covered by a
+	// control, like for a borderless window without a title bar.
+	DragRegionWindow
+	// DragRegionCustom drags the window by Container.DragRect, like a grip.
+	DragRegionCustom
+)
+
+// dragRect returns the area by which the window cnt is dragged besides its
+// title bar, and whether it has one.
+func dragRect(cnt *Container) (image.Rectangle, bool) {
+	switch cnt.DragRegion {
+	case DragRegionWindow:
+		return cnt.Rect, true
+	case DragRegionCustom:
+		r := cnt.DragRect.Add(cnt.Rect.Min).Intersect(cnt.Rect)
+		return r, !r.Empty()
+	}
+	return image.Rectangle{}, false
+}
@@ -0,0 +1,396 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 The Ebitengine Authors
+
+package microui
+
+import (
+	"image"
+	"image/color"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+// textIndexAt returns the byte index of the grapheme cluster boundary in str nearest to
+// the x offset, for str drawn with the paragraph direction dir.
+func textIndexAt(str string, x int, dir int) int {
+	if dir == textDirRTL || hasRTL(str) {
+		return bidiIndexAt(str, x, dir)
+	}
+	var w int
+	for i := 0; i < len(str); {
+		j := nextGrapheme(str, i)
+		rw := textWidth(str[i:j])
+		if x < w+rw/2 {
+			return i
+		}
+		w += rw
+		i = j
+	}
+	return len(str)
+}
+
+// lineAt returns the line index and the byte offset of the line containing
+// the byte offset pos of str.
+func lineAt(str string, pos int) (int, int) {
+	line := strings.Count(str[:pos], "\n")
+	start := strings.LastIndexByte(str[:pos], '\n') + 1
+	return line, start
+}
+
+// lineOffset returns the byte offset of the start of the line index in lines.
+func lineOffset(lines []string, line int) int {
+	var pos int
+	for _, l := range lines[:line] {
+		pos += len(l) + 1
+	}
+	return pos
+}
+
+// editorState is the state of a code editor retained between frames, kept
+// for its text control.
+type editorState struct {
+	caret int
+}
+
+// editorKeys handles the editing keys and the text input of a focused code
+// editor with the state st, and reports whether buf was changed and whether
+// the caret moved.
+func (c *Context) editorKeys(buf *string, st *editorState) (changed, moved bool) {
+	pos := clamp(st.caret, 0, len(*buf))
+	line, start := lineAt(*buf, pos)
+	lines := strings.Split(*buf, "\n")
+
+	insert := func(s string) {
+		*buf = (*buf)[:pos] + s + (*buf)[pos:]
+		pos += len(s)
+		changed = true
+	}
+
+	if len(c.textInput) > 0 {
+		insert(string(c.textInput))
+	}
+	if (c.keyPressed & keyTab) != 0 {
+		// consume Tab so that it doesn't move the focus
+		insert("    ")
+		c.keyPressed &^= keyTab
+	}
+	if (c.keyPressed & keyReturn) != 0 {
+		insert("\n")
+	}
+	if (c.keyPressed&keyBackspace) != 0 && pos > 0 {
+		prev := prevGrapheme(*buf, pos)
+		*buf = (*buf)[:prev] + (*buf)[pos:]
+		pos = prev
+		changed = true
+	}
+	if (c.keyPressed&keyDelete) != 0 && pos < len(*buf) {
+		*buf = (*buf)[:pos] + (*buf)[nextGrapheme(*buf, pos):]
+		changed = true
+	}
+	if changed {
+		st.caret = pos
+		return true, true
+	}
+
+	// handle caret movement
+	switch {
+	case (c.keyPressed&keyLeft) != 0 && pos > 0:
+		pos = prevGrapheme(*buf, pos)
+	case (c.keyPressed&keyRight) != 0 && pos < len(*buf):
+		pos = nextGrapheme(*buf, pos)
+	case (c.keyPressed & keyHome) != 0:
+		pos = start
+	case (c.keyPressed & keyEnd) != 0:
+		pos = start + len(lines[line])
+	case (c.keyPressed & (keyUp | keyDown)) != 0:
+		to := line + 1
+		if (c.keyPressed & keyUp) != 0 {
+			to = line - 1
+		}
+		if to >= 0 && to < len(lines) {
+			x := caretX(lines[line], pos-start, textDirAuto)
+			pos = lineOffset(lines, to) + textIndexAt(lines[to], x, textDirAuto)
+		}
+	}
+	// consume the keys so that they don't scroll the container, nor move the
+	// focus in a popup
+	c.keyPressed &^= keyHome | keyEnd | keyUp | keyDown
+	moved = pos != st.caret
+	st.caret = pos
+	return false, moved
+}
+
+// FindReplace is the state of the find and replace bar of a code editor.
+type FindReplace struct {
+	// Open reports whether the bar is shown.
+	Open    bool
+	Query   string
+	Replace string
+
+	// Matches is the number of matches of Query, updated every frame.
+	Matches int
+	// Current is the index of the selected match counted from 1, or 0 if
+	// there is none, as in a new FindReplace.
+	Current int
+}
+
+// findAll returns the byte offsets of the non-overlapping instances of query
+// in str.
+func findAll(str, query string) []int {
+	if query == "" {
+		return nil
+	}
+	var matches []int
+	for i := 0; ; {
+		j := strings.Index(str[i:], query)
+		if j < 0 {
+			return matches
+		}
+		matches = append(matches, i+j)
+		i += j + len(query)
+	}
+}
+
+// findBar builds the find and replace bar of a code editor for buf with the
+// state st, and returns the offset of the match to reveal, or -1.
+func (c *Context) findBar(buf *string, fr *FindReplace, st *editorState, res *Response) int {
+	matches := findAll(*buf, fr.Query)
+	cur := fr.Current - 1
+	if cur >= len(matches) || len(matches) == 0 {
+		cur = -1
+	}
+	caret := -1
+	if cur >= 0 {
+		caret = matches[cur]
+	}
+
+	reveal := -1
+	next := func(dir int) {
+		if len(matches) == 0 {
+			return
+		}
+		if cur < 0 {
+			cur = 0
+			for i, m := range matches {
+				if m >= st.caret {
+					cur = i
+					break
+				}
+			}
+		} else {
+			cur = (cur + dir + len(matches)) % len(matches)
+		}
+		reveal = matches[cur]
+	}
+
+	c.SetLayoutRow([]int{-160, 24, 24, -1}, 0)
+	if (c.TextBox(&fr.Query) & ResponseSubmit) != 0 {
+		next(1)
+	}
+	if c.Button("<") != 0 {
+		next(-1)
+	}
+	if c.Button(">") != 0 {
+		next(1)
+	}
+	if cur >= 0 {
+		c.Label(strconv.Itoa(cur+1) + "/" + strconv.Itoa(len(matches)))
+	} else {
+		c.Label(strconv.Itoa(len(matches)))
+	}
+
+	c.SetLayoutRow([]int{-160, 60, -1}, 0)
+	c.TextBox(&fr.Replace)
+	if c.Button(c.tr("Replace")) != 0 && caret >= 0 {
+		*buf = (*buf)[:caret] + fr.Replace + (*buf)[caret+len(fr.Query):]
+		*res |= ResponseChange
+		matches = findAll(*buf, fr.Query)
+		cur = -1
+		st.caret = caret + len(fr.Replace)
+		next(1)
+	}
+	if c.Button(c.tr("Replace All")) != 0 && len(matches) > 0 {
+		*buf = strings.ReplaceAll(*buf, fr.Query, fr.Replace)
+		*res |= ResponseChange
+		matches = nil
+		cur = -1
+	}
+	fr.Current = cur + 1
+	fr.Matches = len(matches)
+	return reveal
+}
+
+// CodeEditor is a multi-line text editor for buf with line numbers, meant for
+// code. If tokenize is not nil, it is called for every visible line to split
+// it into colored tokens; the bytes not covered by the tokens are drawn with
+// the text color.
+//
+// The size of the editor is the size of the next layout cell.
+func (c *Context) CodeEditor(buf *string, tokenize func(line string) []Token) Response {
+	return c.codeEditor(buf, tokenize, nil)
+}
+
+// CodeEditorFind is like CodeEditor, but shows a find and replace bar above
+// the editor while fr.Open is true. The matches of the query are highlighted.
+func (c *Context) CodeEditorFind(buf *string, tokenize func(line string) []Token, fr *FindReplace) Response {
+	return c.codeEditor(buf, tokenize, fr)
+}
+
+func (c *Context) codeEditor(buf *string, tokenize func(line string) []Token, fr *FindReplace) Response {
+	var res Response
+	id := c.id(ptrToBytes(unsafe.Pointer(buf)))
+	c.idStack = append(c.idStack, id)
+	defer c.popID()
+	st := WidgetState[editorState](c.Widget(), fnv1a(id, []byte("!text")))
+
+	if fr != nil && fr.Open {
+		c.LayoutColumn(func() {
+			reveal := c.findBar(buf, fr, st, &res)
+			c.SetLayoutRow([]int{-1}, -1)
+			res |= c.codeEditorPanel(id, buf, tokenize, st, fr.Query, reveal)
+		})
+		return res
+	}
+	return c.codeEditorPanel(id, buf, tokenize, st, "", -1)
+}
+
+// codeEditorPanel builds the scrolled text area of a code editor with the
+// state st. The matches of query are highlighted, and if reveal is not
+// negative, the caret is moved to reveal.
+func (c *Context) codeEditorPanel(id ID, buf *string, tokenize func(line string) []Token, st *editorState, query string, reveal int) Response {
+	var res Response
+	c.panelWithID(id, 0, func() {
+		cnt := c.CurrentContainer()
+		lines := strings.Split(*buf, "\n")
+		lh := lineHeight()
+		gutter := textWidth(strconv.Itoa(len(lines))) + c.Style.Padding*2
+
+		// size the content to the longest line
+		w := c.layout().body.Dx()
+		for _, l := range lines {
+			w = max(w, gutter+textWidth(l)+c.Style.Padding)
+		}
+		c.SetLayoutRow([]int{w}, len(lines)*lh)
+
+		tid := c.id([]byte("!text"))
+		c.Control(tid, OptHoldFocus, func(r image.Rectangle) Response {
+			textx := r.Min.X + gutter
+
+			// handle input
+			var moved bool
+			if reveal >= 0 {
+				c.SetFocus(tid)
+				st.caret = reveal
+				moved = true
+			} else if c.focus == tid {
+				if c.mousePressed == mouseLeft && c.mouseOver(r) {
+					line := clamp((c.mousePos.Y-r.Min.Y)/lh, 0, len(lines)-1)
+					st.caret = lineOffset(lines, line) + textIndexAt(lines[line], c.mousePos.X-textx, textDirAuto)
+				}
+				changed, m := c.editorKeys(buf, st)
+				moved = moved || m
+				if changed {
+					res |= ResponseChange
+					lines = strings.Split(*buf, "\n")
+				}
+			}
+
+			// draw text
+			body := cnt.Body
+			c.drawControlFrame(tid, body, ColorBase, 0)
+			textClip := image.Rect(body.Min.X+gutter, body.Min.Y, body.Max.X, body.Max.Y)
+			first := max((body.Min.Y-r.Min.Y)/lh, 0)
+			last := min((body.Max.Y-r.Min.Y)/lh, len(lines)-1)
+			c.pushClipRect(textClip)
+			if query != "" {
+				for i := first; i <= last; i++ {
+					y := r.Min.Y + i*lh
+					for _, m := range findAll(lines[i], query) {
+						x := textx + textWidth(lines[i][:m])
+						hr := image.Rect(x, y, x+textWidth(query), y+lh)
+						c.drawRect(hr, c.Style.Colors[ColorHighlight])
+					}
+				}
+			}
+			for i := first; i <= last; i++ {
+				c.drawCodeLine(lines[i], image.Pt(textx, r.Min.Y+i*lh), tokenize)
+			}
+
+			// draw caret
+			var caret image.Point
+			if c.focus == tid {
+				pos := clamp(st.caret, 0, len(*buf))
+				line, start := lineAt(*buf, pos)
+				caret = image.Pt(caretX(lines[line], pos-start, textDirAuto), line*lh)
+				x, y := textx+caret.X, r.Min.Y+caret.Y
+				if c.caretVisible(tid, pos, len(*buf)) {
+					c.drawRect(image.Rect(x, y, x+1, y+lh), c.Style.Colors[ColorText])
+				}
+			}
+			c.popClipRect()
+
+			// draw line numbers, which are not scrolled horizontally
+			gx := r.Min.X + cnt.Scroll.X
+			for i := first; i <= last; i++ {
+				n := strconv.Itoa(i + 1)
+				c.drawText(n, image.Pt(gx+gutter-c.Style.Padding*2-textWidth(n), r.Min.Y+i*lh), c.Style.Colors[ColorText])
+			}
+
+			// keep the caret visible
+			if moved {
+				viewW := body.Dx() - gutter - c.Style.Padding*2
+				viewH := body.Dy() - c.Style.Padding*2
+				cnt.Scroll.X = clamp(cnt.Scroll.X, caret.X-viewW+1, caret.X)
+				cnt.Scroll.Y = clamp(cnt.Scroll.Y, caret.Y+lh-viewH, caret.Y)
+			}
+			return res
+		})
+	})
+	return res
+}
+
+func (c *Context) drawCodeLine(line string, pos image.Point, tokenize func(line string) []Token) {
+	var tokens []Token
+	if tokenize != nil {
+		tokens = tokenize(line)
+	}
+	if hasRTL(line) {
+		// draw the clusters in visual order, colored by their tokens
+		for _, u := range bidiUnits(line, textDirAuto) {
+			var clr color.Color = c.Style.Colors[ColorText]
+			var i int
+			for _, t := range tokens {
+				if t.Len > 0 && u.start < i+t.Len {
+					if t.Color != nil {
+						clr = t.Color
+					}
+					break
+				}
+				i += max(t.Len, 0)
+			}
+			s := unitText(line, u)
+			c.drawText(s, pos, clr)
+			pos.X += textWidth(s)
+		}
+		return
+	}
+	var i int
+	for _, t := range tokens {
+		if t.Len <= 0 {
+			continue
+		}
+		n := min(t.Len, len(line)-i)
+		var clr color.Color = c.Style.Colors[ColorText]
+		if t.Color != nil {
+			clr = t.Color
+		}
+		c.drawText(line[i:i+n], pos, clr)
+		pos.X += textWidth(line[i : i+n])
+		i += n
+		if i == len(line) {
+			return
+		}
+	}
+	c.drawText(line[i:], pos, c.Style.Colors[ColorText])
+}
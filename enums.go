@@ -15,6 +15,8 @@ const (
 	commandText
 	commandIcon
 	commandDraw
+	commandTransform
+	commandPolyline
 )
 
 const (
@@ -32,7 +34,12 @@ const (
 	ColorBaseFocus
 	ColorScrollBase
 	ColorScrollThumb
-	ColorMax = ColorScrollThumb
+	ColorHighlight
+	ColorError
+	ColorFocusRing
+	ColorDim
+	ColorTextSelection
+	ColorMax = ColorTextSelection
 )
 
 type icon int
@@ -50,9 +57,13 @@ const (
 	ResponseActive Response = (1 << 0)
 	ResponseSubmit Response = (1 << 1)
 	ResponseChange Response = (1 << 2)
+	ResponseCancel Response = (1 << 3)
+	// ResponseInvalid is reported by the controls with a validator when the
+	// validator returns an error for their value.
+	ResponseInvalid Response = (1 << 4)
 )
 
-type Option int
+type Option uint64
 
 const (
 	OptAlignCenter Option = (1 << iota)
@@ -68,6 +79,33 @@ const (
 	OptPopup
 	OptClosed
 	OptExpanded
+	OptLogarithmic
+	OptTicks
+	OptDeferChange
+	OptMinimizable
+	OptModal
+	OptEllipsis
+	OptExpression
+	OptAlignLeft
+	OptLeftToRight
+	OptRightToLeft
+	OptInputPassthrough
+	OptDim
+	OptVertical
+	OptRepeat
+	OptPassword
+	OptContentCenter
+	OptContentBottom
+	OptNoTransition
+	OptNoMove
+)
+
+type TriState int
+
+const (
+	TriUnchecked TriState = iota
+	TriChecked
+	TriMixed
 )
 
 const (
@@ -82,4 +120,19 @@ const (
 	keyAlt       = (1 << 2)
 	keyBackspace = (1 << 3)
 	keyReturn    = (1 << 4)
+	keyLeft      = (1 << 5)
+	keyRight     = (1 << 6)
+	keyUp        = (1 << 7)
+	keyDown      = (1 << 8)
+	keyHome      = (1 << 9)
+	keyEnd       = (1 << 10)
+	keyPageUp    = (1 << 11)
+	keyPageDown  = (1 << 12)
+	keyEscape    = (1 << 13)
+	keyTab       = (1 << 14)
+	keyDelete    = (1 << 15)
+	keySpace     = (1 << 16)
+	keyC         = (1 << 17)
+	keyV         = (1 << 18)
+	keyX         = (1 << 19)
 )
@@ -0,0 +1,49 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 The Ebitengine Authors
+
+package microui
+
+// Event is a submit or a change of a control, sent to Context.EventSink.
+type Event struct {
+	// ID is the ID of the control.
+	ID ID
+	// Kind is ResponseSubmit or ResponseChange.
+	Kind Response
+	// Old and New are the values of the control before and after the change,
+	// like a bool for a check box, a float64 for a slider or a number and a
+	// string for a text box. They are nil for the controls without a value,
+	// like buttons.
+	Old, New any
+	// Window is the title of the window of the control.
+	Window string
+}
+
+// eventValues sets the values of the event of the control being built.
+func (c *Context) eventValues(old, new any) {
+	c.eventOld, c.eventNew = old, new
+}
+
+// emitEvents sends the events of the response res of the control id to the
+// event sink.
+func (c *Context) emitEvents(id ID, res Response) {
+	old, new := c.eventOld, c.eventNew
+	c.eventOld, c.eventNew = nil, nil
+	if c.EventSink == nil || id == 0 || c.muteEvents > 0 {
+		return
+	}
+	var window string
+	if cnt := c.rootContainer(); cnt != nil {
+		window = cnt.name
+	}
+	for _, kind := range []Response{ResponseSubmit, ResponseChange} {
+		if (res & kind) != 0 {
+			c.EventSink(Event{
+				ID:     id,
+				Kind:   kind,
+				Old:    old,
+				New:    new,
+				Window: window,
+			})
+		}
+	}
+}
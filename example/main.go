@@ -21,13 +21,18 @@ type Game struct {
 	checks       [3]bool
 	num1         float64
 	num2         float64
+	script       string
+	scriptFind   microui.FindReplace
 }
 
 func New() *Game {
+	ctx := microui.NewContext()
+	ctx.KeyboardNavigation = true
 	return &Game{
-		ctx:    microui.NewContext(),
+		ctx:    ctx,
 		bg:     [3]float64{90, 95, 100},
 		checks: [3]bool{true, false, true},
+		script: "// Script\nfunc main() {\n    println(\"Hello, World!\")\n}\n",
 	}
 }
 
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"image"
 	"image/color"
+	"strings"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/text/v2"
@@ -73,6 +74,19 @@ func (g *Game) testWindow() {
 		if g.ctx.HeaderEx("Tree and Text", microui.OptExpanded) != 0 {
 			g.ctx.SetLayoutRow([]int{140, -1}, 0)
 			g.ctx.LayoutColumn(func() {
+				g.ctx.SetLayoutRow([]int{67, 67}, 0)
+				var expanded, collapsed bool
+				if g.ctx.Button("Expand") != 0 {
+					expanded = true
+				}
+				if g.ctx.Button("Collapse") != 0 {
+					collapsed = true
+				}
+				for _, label := range []string{"Test 1", "Test 2", "Test 3"} {
+					if expanded || collapsed {
+						g.ctx.SetTreeNodeExpandedRecursive(g.ctx.TreeNodeID(label), expanded)
+					}
+				}
 				g.ctx.TreeNode("Test 1", func(res microui.Response) {
 					g.ctx.TreeNode("Test 1a", func(res microui.Response) {
 						g.ctx.Label("Hello")
@@ -198,7 +212,7 @@ func (g *Game) byteSlider(fvalue *float64, value *byte, low, high byte) microui.
 }
 
 var (
-	fcolors = [14]struct {
+	fcolors = [microui.ColorMax + 1]struct {
 		R, G, B, A float64
 	}{}
 	colors = []struct {
@@ -219,6 +233,11 @@ var (
 		{"basefocus:", microui.ColorBaseFocus},
 		{"scrollbase:", microui.ColorScrollBase},
 		{"scrollthumb:", microui.ColorScrollThumb},
+		{"highlight:", microui.ColorHighlight},
+		{"error:", microui.ColorError},
+		{"focusring:", microui.ColorFocusRing},
+		{"dim:", microui.ColorDim},
+		{"selection:", microui.ColorTextSelection},
 	}
 )
 
@@ -250,10 +269,30 @@ func (g *Game) styleWindow() {
 	})
 }
 
+func tokenizeScript(line string) []microui.Token {
+	if i := strings.Index(line, "//"); i >= 0 {
+		return []microui.Token{
+			{Len: i},
+			{Len: len(line) - i, Color: color.RGBA{120, 160, 120, 255}},
+		}
+	}
+	return nil
+}
+
+func (g *Game) scriptWindow() {
+	g.ctx.Window("Script", image.Rect(660, 40, 960, 240), func(res microui.Response) {
+		g.ctx.SetLayoutRow([]int{-1}, 0)
+		g.ctx.Checkbox("Find and Replace", &g.scriptFind.Open)
+		g.ctx.SetLayoutRow([]int{-1}, -1)
+		g.ctx.CodeEditorFind(&g.script, tokenizeScript, &g.scriptFind)
+	})
+}
+
 func (g *Game) ProcessFrame() {
 	g.ctx.Update(func() {
 		g.testWindow()
 		g.logWindow()
 		g.styleWindow()
+		g.scriptWindow()
 	})
 }
@@ -0,0 +1,128 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 The Ebitengine Authors
+
+package microui
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var errExpr = errors.New("microui: invalid expression")
+
+// FilterExpression accepts the runes of an arithmetic expression.
+func FilterExpression(r rune) bool {
+	return FilterNumeric(r) || strings.ContainsRune("*/() ", r)
+}
+
+// evalExpr evaluates an arithmetic expression made of numbers, the operators
+// + - * / and parentheses. A result which isn't finite, like a division by
+// zero, is an error.
+func evalExpr(str string) (float64, error) {
+	p := exprParser{str: strings.ReplaceAll(str, " ", "")}
+	v, err := p.sum()
+	if err != nil {
+		return 0, err
+	}
+	if p.pos != len(p.str) || math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, errExpr
+	}
+	return v, nil
+}
+
+type exprParser struct {
+	str string
+	pos int
+}
+
+func (p *exprParser) peek() byte {
+	if p.pos < len(p.str) {
+		return p.str[p.pos]
+	}
+	return 0
+}
+
+// sum parses terms separated by + and -.
+func (p *exprParser) sum() (float64, error) {
+	v, err := p.product()
+	if err != nil {
+		return 0, err
+	}
+	for p.peek() == '+' || p.peek() == '-' {
+		op := p.peek()
+		p.pos++
+		w, err := p.product()
+		if err != nil {
+			return 0, err
+		}
+		if op == '+' {
+			v += w
+		} else {
+			v -= w
+		}
+	}
+	return v, nil
+}
+
+// product parses factors separated by * and /.
+func (p *exprParser) product() (float64, error) {
+	v, err := p.factor()
+	if err != nil {
+		return 0, err
+	}
+	for p.peek() == '*' || p.peek() == '/' {
+		op := p.peek()
+		p.pos++
+		w, err := p.factor()
+		if err != nil {
+			return 0, err
+		}
+		if op == '*' {
+			v *= w
+		} else {
+			v /= w
+		}
+	}
+	return v, nil
+}
+
+// factor parses a signed number or a parenthesized expression.
+func (p *exprParser) factor() (float64, error) {
+	switch p.peek() {
+	case '-':
+		p.pos++
+		v, err := p.factor()
+		return -v, err
+	case '+':
+		p.pos++
+		return p.factor()
+	case '(':
+		p.pos++
+		v, err := p.sum()
+		if err != nil {
+			return 0, err
+		}
+		if p.peek() != ')' {
+			return 0, errExpr
+		}
+		p.pos++
+		return v, nil
+	}
+	start := p.pos
+	for p.pos < len(p.str) {
+		b := p.str[p.pos]
+		// accept the sign of an exponent
+		exp := (b == '+' || b == '-') && p.pos > start && (p.str[p.pos-1] == 'e' || p.str[p.pos-1] == 'E')
+		if !exp && !(b >= '0' && b <= '9') && b != '.' && b != 'e' && b != 'E' {
+			break
+		}
+		p.pos++
+	}
+	v, err := strconv.ParseFloat(p.str[start:p.pos], 64)
+	if err != nil {
+		return 0, errExpr
+	}
+	return v, nil
+}
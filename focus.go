@@ -0,0 +1,183 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 The Ebitengine Authors
+
+package microui
+
+import (
+	"image"
+	"strings"
+)
+
+// rootContainer returns the root container of the current container, or nil.
+func (c *Context) rootContainer() *Container {
+	for i := len(c.containerStack) - 1; i >= 0; i-- {
+		// only root containers have their `head` field set
+		if c.containerStack[i].HeadIdx >= 0 {
+			return c.containerStack[i]
+		}
+	}
+	return nil
+}
+
+// addTabStop makes the control id reachable with the Tab key.
+func (c *Context) addTabStop(id ID) {
+	c.tabStops = append(c.tabStops, tabStop{id: id, root: c.rootContainer()})
+}
+
+// keyActivated reports whether one of keys was pressed while the focus was
+// moved with the keyboard, in keyboard navigation mode.
+func (c *Context) keyActivated(keys int) bool {
+	return c.KeyboardNavigation && c.focusByKey && (c.keyPressed&keys) != 0
+}
+
+// activated reports whether the focused control id was clicked, or activated
+// with Enter or Space.
+func (c *Context) activated(id ID) bool {
+	return c.focus == id && (c.mousePressed == mouseLeft || c.keyActivated(keyReturn|keySpace))
+}
+
+// revealFocus scrolls the current container to show rect, the area of the
+// focused control, after the focus was moved with the keyboard.
+func (c *Context) revealFocus(rect image.Rectangle) {
+	c.focusMoved = false
+	cnt := c.CurrentContainer()
+	if rect.Min.Y < cnt.Body.Min.Y {
+		cnt.Scroll.Y -= cnt.Body.Min.Y - rect.Min.Y
+	} else if rect.Max.Y > cnt.Body.Max.Y {
+		cnt.Scroll.Y += rect.Max.Y - cnt.Body.Max.Y
+	}
+}
+
+// pageFocusContainer scrolls the container of the focused control, or else
+// the container under the mouse, by a page with PageUp and PageDown, and to
+// the top or the bottom with Home and End, unless the control used these
+// keys.
+func (c *Context) pageFocusContainer() {
+	cnt := c.focusContainer
+	if cnt == nil {
+		cnt = c.scrollTarget
+	}
+	if cnt == nil {
+		return
+	}
+	if (c.keyPressed & keyPageUp) != 0 {
+		cnt.Scroll.Y -= cnt.Body.Dy()
+	}
+	if (c.keyPressed & keyPageDown) != 0 {
+		cnt.Scroll.Y += cnt.Body.Dy()
+	}
+	if (c.keyPressed & keyHome) != 0 {
+		cnt.Scroll.Y = 0
+	}
+	if (c.keyPressed & keyEnd) != 0 {
+		cnt.Scroll.Y = cnt.ContentSize.Y
+	}
+	c.clampScroll(cnt)
+}
+
+// focusNextWindow brings the back-most window to front and focuses its first
+// control, so that repeating it cycles through the windows.
+func (c *Context) focusNextWindow() {
+	if c.nextModal != nil {
+		return
+	}
+	var next *Container
+	for _, cnt := range c.rootList {
+		if cnt.name == "" || strings.HasPrefix(cnt.name, "!") {
+			continue
+		}
+		if next == nil || behind(cnt, next) {
+			next = cnt
+		}
+	}
+	if next == nil {
+		return
+	}
+	c.bringToFront(next)
+	c.focus = 0
+	for _, s := range c.tabStops {
+		if s.root == next {
+			c.focus = s.id
+			c.focusByKey = true
+			c.focusMoved = true
+			break
+		}
+	}
+}
+
+// focusNext moves the focus to the next control, or the previous one if
+// backward is true, in the order they were built. While a modal window is
+// open, only its controls can be focused.
+func (c *Context) focusNext(backward bool) {
+	c.cycleFocus(backward, func(s tabStop) bool {
+		return c.nextModal == nil || s.root == c.nextModal
+	})
+}
+
+// focusPopupItem moves the focus to the next control of the front-most open
+// popup, or the previous one if backward is true, so that the items of a
+// popup menu are chosen with the Up and Down keys.
+func (c *Context) focusPopupItem(backward bool) {
+	var popup *Container
+	for _, s := range c.tabStops {
+		if s.root != nil && s.root.popup && s.root.Open && (popup == nil || behind(popup, s.root)) {
+			popup = s.root
+		}
+	}
+	if popup == nil {
+		return
+	}
+	c.cycleFocus(backward, func(s tabStop) bool {
+		return s.root == popup
+	})
+}
+
+// cycleFocus moves the focus to the next tab stop accepted by in, or the
+// previous one if backward is true, wrapping around.
+func (c *Context) cycleFocus(backward bool, in func(s tabStop) bool) {
+	var stops []ID
+	for _, s := range c.tabStops {
+		if in(s) {
+			stops = append(stops, s.id)
+		}
+	}
+	if len(stops) == 0 {
+		return
+	}
+
+	i := -1
+	for j, id := range stops {
+		if id == c.focus {
+			i = j
+			break
+		}
+	}
+	switch {
+	case i < 0 && backward:
+		i = len(stops) - 1
+	case i < 0:
+		i = 0
+	case backward:
+		i = (i + len(stops) - 1) % len(stops)
+	default:
+		i = (i + 1) % len(stops)
+	}
+	c.focus = stops[i]
+	c.focusByKey = true
+	c.focusMoved = true
+}
+
+// trapFocus moves the focus back into the modal window, if any, when it was
+// moved out of it.
+func (c *Context) trapFocus() {
+	if c.nextModal == nil || c.focus == 0 {
+		return
+	}
+	for _, id := range c.modalIDs {
+		if id == c.focus {
+			return
+		}
+	}
+	c.focus = 0
+	c.focusNext(false)
+}
@@ -0,0 +1,103 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 The Ebitengine Authors
+
+package microui
+
+import (
+	"fmt"
+	"image"
+	"strconv"
+	"unsafe"
+)
+
+// valueFormat is the format of the value of a slider or a number control: the
+// fmt verb verb, or fn if it is not nil.
+type valueFormat struct {
+	verb string
+	fn   func(v float64) string
+}
+
+// formattedValue is the text of the value v formatted with the verb verb.
+type formattedValue struct {
+	verb string
+	v    float64
+	text string
+}
+
+// formatValue returns the text of the control id showing v with format. The
+// text of a verb formatted by the default localizer is kept while the value
+// doesn't change, instead of being formatted again every frame.
+func (c *Context) formatValue(id ID, format valueFormat, v float64) string {
+	if format.fn != nil {
+		return format.fn(v)
+	}
+	if _, ok := c.Localizer.(englishLocalizer); !ok || id == 0 {
+		return c.Localizer.Number(format.verb, v)
+	}
+	f := WidgetState[formattedValue](c.Widget(), fnv1a(id, []byte("!text")))
+	if f.text == "" || f.verb != format.verb || f.v != v {
+		*f = formattedValue{verb: format.verb, v: v, text: c.Localizer.Number(format.verb, v)}
+	}
+	return f.text
+}
+
+// copyValueClick is like copyClick for the value v of a control, which is
+// only formatted with format when it is copied.
+func (c *Context) copyValueClick(r image.Rectangle, format valueFormat, v float64) bool {
+	if !c.copyClicked(r) {
+		return false
+	}
+	if format.fn != nil {
+		return c.copyClick(r, format.fn(v))
+	}
+	return c.copyClick(r, fmt.Sprintf(format.verb, v))
+}
+
+// editText returns the text of v in the text input mode. A value formatted by
+// a function is edited as the raw value.
+func (f valueFormat) editText(v float64) string {
+	if f.fn != nil {
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+	return formatEditValue(v, f.verb)
+}
+
+// unit returns the unit of the format, which can be typed in the text input
+// mode.
+func (f valueFormat) unit() string {
+	if f.fn != nil {
+		return ""
+	}
+	return formatUnit(f.verb)
+}
+
+// FormatPercent returns a formatter showing a ratio as a percentage with the
+// given number of decimals, like "25%" for 0.25.
+func FormatPercent(decimals int) func(v float64) string {
+	return func(v float64) string {
+		return strconv.FormatFloat(v*100, 'f', decimals, 64) + "%"
+	}
+}
+
+// FormatRatio returns a formatter showing a value as a ratio to 1 with the
+// given number of decimals, like "1.78:1" for 1.777.
+func FormatRatio(decimals int) func(v float64) string {
+	return func(v float64) string {
+		return strconv.FormatFloat(v, 'f', decimals, 64) + ":1"
+	}
+}
+
+// SliderFormatted is like SliderEx, but shows the value as formatted by
+// format, like FormatPercent(0). The value is still edited as is in the text
+// input mode.
+func (c *Context) SliderFormatted(value *float64, low, high, step float64, format func(v float64) string, opt Option) Response {
+	id := c.id(ptrToBytes(unsafe.Pointer(value)))
+	return c.slider(value, id, low, high, step, nil, nil, valueFormat{fn: format}, opt)
+}
+
+// NumberFormatted is like NumberEx, but shows the value as formatted by
+// format, like SliderFormatted.
+func (c *Context) NumberFormatted(value *float64, step float64, format func(v float64) string, opt Option) Response {
+	id := c.id(ptrToBytes(unsafe.Pointer(value)))
+	return c.number(value, id, step, valueFormat{fn: format}, opt)
+}
@@ -0,0 +1,137 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 The Ebitengine Authors
+
+package microui
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+const glyphAtlasSize = 1024
+
+// glyphAtlas packs the glyphs of a font face in a single texture, so that the
+// text of a frame is drawn with batched quads.
+type glyphAtlas struct {
+	image  *ebiten.Image
+	glyphs map[*ebiten.Image]image.Rectangle
+
+	// shelf packing state
+	x, y, rowHeight int
+
+	layout   []text.Glyph
+	vertices []ebiten.Vertex
+	indices  []uint16
+}
+
+func newGlyphAtlas() *glyphAtlas {
+	return &glyphAtlas{
+		image:  ebiten.NewImage(glyphAtlasSize, glyphAtlasSize),
+		glyphs: map[*ebiten.Image]image.Rectangle{},
+	}
+}
+
+// SetFontFace sets the font face used to measure and draw the text of the
+// UI. A nil face restores the default font.
+//
+// The glyphs of a custom face are cached in an atlas, and the text of a
+// frame is drawn with a draw call per clip region.
+func SetFontFace(face text.Face) {
+	if face == nil {
+		fontFace = defaultFontFace
+		atlas = nil
+		return
+	}
+	fontFace = face
+	atlas = newGlyphAtlas()
+}
+
+// reset clears the atlas once it is full.
+func (a *glyphAtlas) reset() {
+	a.image.Clear()
+	clear(a.glyphs)
+	a.x, a.y, a.rowHeight = 0, 0, 0
+}
+
+// glyphRect returns the area of img in the atlas, adding it if needed. It
+// returns false if img doesn't fit in the atlas.
+func (a *glyphAtlas) glyphRect(img *ebiten.Image) (image.Rectangle, bool) {
+	if r, ok := a.glyphs[img]; ok {
+		return r, true
+	}
+	// keep a pixel between the glyphs to avoid bleeding when filtering
+	size := img.Bounds().Size()
+	if a.x+size.X+1 > glyphAtlasSize {
+		a.x = 0
+		a.y += a.rowHeight + 1
+		a.rowHeight = 0
+	}
+	if a.y+size.Y+1 > glyphAtlasSize {
+		return image.Rectangle{}, false
+	}
+	r := image.Rectangle{Min: image.Pt(a.x, a.y), Max: image.Pt(a.x+size.X, a.y+size.Y)}
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(float64(r.Min.X-img.Bounds().Min.X), float64(r.Min.Y-img.Bounds().Min.Y))
+	a.image.DrawImage(img, op)
+	a.glyphs[img] = r
+	a.x += size.X + 1
+	a.rowHeight = max(a.rowHeight, size.Y)
+	return r, true
+}
+
+// appendText adds the quads of str at pos to the batch, flushing the batch to
+// target first if needed.
+func (a *glyphAtlas) appendText(target *ebiten.Image, str string, pos image.Point, clr color.RGBA64, t transform) {
+	a.layout = text.AppendGlyphs(a.layout[:0], str, fontFace, nil)
+	cr := float32(clr.R) / 0xffff
+	cg := float32(clr.G) / 0xffff
+	cb := float32(clr.B) / 0xffff
+	ca := float32(clr.A) / 0xffff
+	for _, g := range a.layout {
+		if g.Image == nil {
+			continue
+		}
+		if len(a.vertices)+4 > ebiten.MaxVertexCount || len(a.indices)+6 > ebiten.MaxIndicesCount {
+			a.flush(target)
+		}
+		src, ok := a.glyphRect(g.Image)
+		if !ok {
+			// the quads already batched use the current atlas
+			a.flush(target)
+			a.reset()
+			if src, ok = a.glyphRect(g.Image); !ok {
+				continue
+			}
+		}
+		gx, gy := t.apply(float64(pos.X)+g.X, float64(pos.Y)+g.Y)
+		x, y := float32(gx), float32(gy)
+		s := float32(t.scaleOrOne())
+		w, h := float32(src.Dx())*s, float32(src.Dy())*s
+		sx, sy := float32(src.Min.X), float32(src.Min.Y)
+		sw, sh := float32(src.Dx()), float32(src.Dy())
+		n := uint16(len(a.vertices))
+		a.vertices = append(a.vertices,
+			ebiten.Vertex{DstX: x, DstY: y, SrcX: sx, SrcY: sy, ColorR: cr, ColorG: cg, ColorB: cb, ColorA: ca},
+			ebiten.Vertex{DstX: x + w, DstY: y, SrcX: sx + sw, SrcY: sy, ColorR: cr, ColorG: cg, ColorB: cb, ColorA: ca},
+			ebiten.Vertex{DstX: x, DstY: y + h, SrcX: sx, SrcY: sy + sh, ColorR: cr, ColorG: cg, ColorB: cb, ColorA: ca},
+			ebiten.Vertex{DstX: x + w, DstY: y + h, SrcX: sx + sw, SrcY: sy + sh, ColorR: cr, ColorG: cg, ColorB: cb, ColorA: ca},
+		)
+		a.indices = append(a.indices, n, n+1, n+2, n+1, n+3, n+2)
+	}
+}
+
+// flush draws the batched quads to target.
+func (a *glyphAtlas) flush(target *ebiten.Image) {
+	if len(a.indices) == 0 {
+		return
+	}
+	op := &ebiten.DrawTrianglesOptions{
+		ColorScaleMode: ebiten.ColorScaleModePremultipliedAlpha,
+	}
+	target.DrawTriangles(a.vertices, a.indices, a.image, op)
+	a.vertices = a.vertices[:0]
+	a.indices = a.indices[:0]
+}
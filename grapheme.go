@@ -0,0 +1,104 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 The Ebitengine Authors
+
+package microui
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const zeroWidthJoiner = 0x200d
+
+// isGraphemeExtend reports whether r extends the grapheme cluster before it,
+// like combining marks, variation selectors and emoji modifiers.
+func isGraphemeExtend(r rune) bool {
+	switch {
+	case r == zeroWidthJoiner:
+		return true
+	case r >= 0xfe00 && r <= 0xfe0f, r >= 0xe0100 && r <= 0xe01ef:
+		// variation selectors
+		return true
+	case r >= 0x1f3fb && r <= 0x1f3ff:
+		// emoji skin tone modifiers
+		return true
+	case r >= 0xe0020 && r <= 0xe007f:
+		// tags
+		return true
+	}
+	return unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc)
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1f1e6 && r <= 0x1f1ff
+}
+
+// nextGrapheme returns the byte offset of the end of the grapheme cluster
+// starting at the byte offset pos of str.
+//
+// This is a simplified segmentation covering combining marks, emoji
+// sequences joined with ZWJ, emoji modifiers, flags and CRLF.
+func nextGrapheme(str string, pos int) int {
+	if pos >= len(str) {
+		return len(str)
+	}
+	r, n := utf8.DecodeRuneInString(str[pos:])
+	pos += n
+	if r == '\r' {
+		if pos < len(str) && str[pos] == '\n' {
+			pos++
+		}
+		return pos
+	}
+	if r == '\n' {
+		return pos
+	}
+	if isRegionalIndicator(r) {
+		// flags are pairs of regional indicators
+		if r, n := utf8.DecodeRuneInString(str[pos:]); isRegionalIndicator(r) {
+			pos += n
+		}
+	}
+	for pos < len(str) {
+		r, n := utf8.DecodeRuneInString(str[pos:])
+		if !isGraphemeExtend(r) {
+			break
+		}
+		pos += n
+		if r == zeroWidthJoiner && pos < len(str) {
+			// the joiner glues the next character to the cluster
+			if r, n := utf8.DecodeRuneInString(str[pos:]); r != '\n' && r != '\r' {
+				pos += n
+			}
+		}
+	}
+	return pos
+}
+
+// prevGrapheme returns the byte offset of the start of the grapheme cluster
+// ending at the byte offset pos of str.
+func prevGrapheme(str string, pos int) int {
+	if pos <= 0 {
+		return 0
+	}
+	// clusters don't span lines, so segment from the start of the line
+	i := strings.LastIndexByte(str[:pos-1], '\n') + 1
+	for {
+		j := nextGrapheme(str, i)
+		if j >= pos {
+			return i
+		}
+		i = j
+	}
+}
+
+// runeStart returns the byte offset pos of str, clamped to the length of str
+// and moved back to the start of the rune containing it.
+func runeStart(str string, pos int) int {
+	pos = clamp(pos, 0, len(str))
+	for pos > 0 && pos < len(str) && !utf8.RuneStart(str[pos]) {
+		pos--
+	}
+	return pos
+}
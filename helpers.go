@@ -6,6 +6,8 @@ package microui
 import (
 	"image"
 	"sort"
+	"strings"
+	"time"
 	"unsafe"
 )
 
@@ -29,6 +31,13 @@ func max(a, b int) int {
 	return b
 }
 
+func max64(a, b time.Duration) time.Duration {
+	if a > b {
+		return a
+	}
+	return b
+}
+
 func minF(a, b float64) float64 {
 	if a < b {
 		return a
@@ -43,6 +52,13 @@ func maxF(a, b float64) float64 {
 	return b
 }
 
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
 func clamp(x, a, b int) int {
 	return min(b, max(a, x))
 }
@@ -51,6 +67,20 @@ func clampF(x, a, b float64) float64 {
 	return minF(b, maxF(a, x))
 }
 
+// indexFold returns the byte index of the first instance of substr in s under
+// simple case folding, or -1 if it is not present.
+func indexFold(s, substr string) int {
+	for i := range s {
+		if len(s)-i < len(substr) {
+			break
+		}
+		if strings.EqualFold(s[i:i+len(substr)], substr) {
+			return i
+		}
+	}
+	return -1
+}
+
 func fnv1a(init ID, data []byte) ID {
 	h := init
 	for i := 0; i < len(data); i++ {
@@ -125,6 +155,9 @@ func (c *Context) popContainer() {
 	layout := c.layout()
 	cnt.ContentSize.X = layout.max.X - layout.body.Min.X
 	cnt.ContentSize.Y = layout.max.Y - layout.body.Min.Y
+	// re-clamp the scroll in case the content shrank, so that the view doesn't
+	// show a blank area below the content
+	c.clampScroll(cnt)
 	// pop container, layout and id
 	// pop()
 	c.containerStack = c.containerStack[:len(c.containerStack)-1]
@@ -132,15 +165,37 @@ func (c *Context) popContainer() {
 	c.layoutStack = c.layoutStack[:len(c.layoutStack)-1]
 }
 
+func (c *Context) clampScroll(cnt *Container) {
+	p := c.containerStyle(cnt).Padding
+	cs := cnt.ContentSize.Add(image.Pt(p*2, p*2))
+	maxscroll := cs.Sub(cnt.Body.Size())
+	cnt.Scroll.X = clamp(cnt.Scroll.X, 0, max(maxscroll.X, 0))
+	cnt.Scroll.Y = clamp(cnt.Scroll.Y, 0, max(maxscroll.Y, 0))
+}
+
+// AnchorScroll anchors the scroll of the current container to the last
+// control, identified by id: when the content above the control changes
+// size, like a collapsed tree node, the container is scrolled to keep the
+// control at the same position in the view.
+func (c *Context) AnchorScroll(id ID) {
+	cnt := c.CurrentContainer()
+	y := c.lastRect.Min.Y - cnt.Body.Min.Y + cnt.Scroll.Y
+	if cnt.anchor == id {
+		cnt.Scroll.Y += y - cnt.anchorY
+	}
+	cnt.anchor = id
+	cnt.anchorY = y
+}
+
 func (c *Context) CurrentContainer() *Container {
 	return c.containerStack[len(c.containerStack)-1]
 }
 
 func (c *Context) container(id ID, opt Option) *Container {
 	// try to get existing container from pool
-	if idx := c.poolGet(c.containerPool[:], id); idx >= 0 {
+	if idx := c.poolGet(c.containerPool, id); idx >= 0 {
 		if c.containers[idx].Open || (^opt&OptClosed) != 0 {
-			c.poolUpdate(c.containerPool[:], idx)
+			c.poolUpdate(c.containerPool, idx)
 		}
 		return &c.containers[idx]
 	}
@@ -149,8 +204,15 @@ func (c *Context) container(id ID, opt Option) *Container {
 		return nil
 	}
 
-	// container not found in pool: init new container
-	idx := c.poolInit(c.containerPool[:], id)
+	// container not found in pool: init new container. a window can't be
+	// built without a container, so a pinned one is reused if every container
+	// is pinned
+	idx := c.poolInit(c.containerPool, id)
+	if idx < 0 {
+		idx = c.poolLRU(c.containerPool, false)
+		expect(idx > -1)
+		c.poolSet(c.containerPool, idx, id)
+	}
 	cnt := &c.containers[idx]
 	*cnt = Container{}
 	cnt.HeadIdx = -1
@@ -160,28 +222,139 @@ func (c *Context) container(id ID, opt Option) *Container {
 	return cnt
 }
 
+// containerStyle returns the style of the container cnt, overriding the style
+// of the context.
+func (c *Context) containerStyle(cnt *Container) ContainerStyle {
+	if cnt.Style != nil {
+		return *cnt.Style
+	}
+	return c.Style.ContainerStyle()
+}
+
 func (c *Context) Container(name string) *Container {
 	id := c.id([]byte(name))
 	return c.container(id, 0)
 }
 
-func (c *Context) bringToFront(cnt *Container) {
-	c.lastZIndex++
-	cnt.ZIndex = c.lastZIndex
+// Windows returns the open windows of the context, sorted from back to front.
+// The closed windows and the internal windows like tooltips are not included.
+func (c *Context) Windows() []WindowInfo {
+	var windows []WindowInfo
+	for i := range c.containers {
+		cnt := &c.containers[i]
+		if c.containerPool[i].id == 0 || !cnt.Open || cnt.name == "" || strings.HasPrefix(cnt.name, "!") {
+			continue
+		}
+		windows = append(windows, WindowInfo{
+			Name:   cnt.name,
+			Rect:   cnt.Rect,
+			Open:   cnt.Open,
+			ZIndex: cnt.ZIndex,
+			Layer:  cnt.Layer,
+		})
+	}
+	sort.Slice(windows, func(i, j int) bool {
+		if windows[i].Layer != windows[j].Layer {
+			return windows[i].Layer < windows[j].Layer
+		}
+		return windows[i].ZIndex < windows[j].ZIndex
+	})
+	return windows
+}
+
+// windowContainer returns the container of the window named name, creating it if it is
+// not known yet.
+func (c *Context) windowContainer(name string) *Container {
+	if cnt := c.findWindow(name); cnt != nil {
+		return cnt
+	}
+	// the windows are built outside of any other container, so their IDs are
+	// hashed from the root, even if this is called while building a window
+	stack := c.idStack
+	c.idStack = nil
+	cnt := c.Container(name)
+	c.idStack = stack
+	cnt.name = name
+	return cnt
+}
+
+// OpenWindow opens the window named name.
+func (c *Context) OpenWindow(name string) {
+	c.windowContainer(name).Open = true
+}
+
+// CloseWindow closes the window named name.
+func (c *Context) CloseWindow(name string) {
+	c.windowContainer(name).Open = false
+}
+
+// FocusWindow opens the window named name and brings it to front.
+func (c *Context) FocusWindow(name string) {
+	cnt := c.windowContainer(name)
+	cnt.Open = true
+	c.bringToFront(cnt)
+}
+
+// Taskbar builds a strip at rect with a button for every minimized window,
+// in the order they were minimized. Clicking a button restores its window.
+func (c *Context) Taskbar(rect image.Rectangle) {
+	var minimized []*Container
+	for i := range c.containers {
+		cnt := &c.containers[i]
+		if c.containerPool[i].id != 0 && cnt.Open && cnt.Minimized {
+			minimized = append(minimized, cnt)
+		}
+	}
+	sort.Slice(minimized, func(i, j int) bool {
+		return minimized[i].minimizedAt < minimized[j].minimizedAt
+	})
+
+	cnt := c.Container("!taskbar")
+	cnt.Rect = rect
+	opt := OptNoTitle | OptNoResize | OptNoScroll
+	c.window("!taskbar", rect, opt, func(res Response) {
+		widths := make([]int, len(minimized))
+		for i, m := range minimized {
+			widths[i] = textWidth(m.name) + c.Style.Padding*2
+		}
+		c.SetLayoutRow(widths, -1)
+		for _, m := range minimized {
+			if c.Button(m.name) != 0 {
+				m.Minimized = false
+			}
+		}
+	})
 }
 
 func (c *Context) SetFocus(id ID) {
 	c.focus = id
 	c.keepFocus = true
+	c.focusByKey = false
+	c.focusMoved = false
 }
 
 func (c *Context) Update(f func()) {
 	c.begin()
 	defer c.end()
 	f()
+	c.annotationWindow()
+	c.tooltipWindow()
+	c.dragPreviewWindow()
+}
+
+// SetDeltaTime sets the duration between two frames, which drives the
+// timings of the interactions like tooltip delays and key repeats. By default,
+// it is the duration of a tick of the game.
+func (c *Context) SetDeltaTime(dt time.Duration) {
+	c.deltaTime = dt
 }
 
 func (c *Context) begin() {
+	dt := c.deltaTime
+	if dt <= 0 {
+		dt = defaultDeltaTime()
+	}
+	c.now += dt
 	c.updateInput()
 
 	c.commandList = c.commandList[:0]
@@ -189,9 +362,20 @@ func (c *Context) begin() {
 	c.scrollTarget = nil
 	c.hoverRoot = c.nextHoverRoot
 	c.nextHoverRoot = nil
+	c.modal = c.nextModal
+	c.nextModal = nil
+	c.tabStops = c.tabStops[:0]
+	c.modalIDs = c.modalIDs[:0]
 	c.mouseDelta.X = c.mousePos.X - c.lastMousePos.X
 	c.mouseDelta.Y = c.mousePos.Y - c.lastMousePos.Y
+	// a press is a click if the mouse doesn't move until it is released
+	if (c.mousePressed & mouseLeft) != 0 {
+		c.clickMoved = false
+	} else if c.mouseDelta != (image.Point{}) {
+		c.clickMoved = true
+	}
 	c.tick++
+	c.applyContainerUpdates()
 }
 
 func (c *Context) end() {
@@ -213,6 +397,28 @@ func (c *Context) end() {
 	}
 	c.keepFocus = false
 
+	// Escape drops the focus; the controls being edited have already canceled
+	// their edits
+	if (c.keyPressed & keyEscape) != 0 {
+		c.focus = 0
+	}
+
+	// handle focus navigation, and keep the focus in the modal window if any
+	if c.KeyboardNavigation && (c.keyPressed&keyTab) != 0 && (c.keyDown&keyControl) != 0 {
+		c.focusNextWindow()
+	} else if (c.keyPressed & keyTab) != 0 {
+		c.focusNext((c.keyDown & keyShift) != 0)
+	} else if c.KeyboardNavigation && (c.keyPressed&(keyUp|keyDown)) != 0 {
+		c.focusPopupItem((c.keyPressed & keyUp) != 0)
+	}
+	c.pageFocusContainer()
+	c.focusContainer = nil
+	c.trapFocus()
+
+	c.swapProfile()
+	c.checkBudget()
+	c.clearTreeNodeRequests()
+
 	// bring hover root to front if mouse was pressed
 	if c.mousePressed != 0 && c.nextHoverRoot != nil &&
 		c.nextHoverRoot.ZIndex < c.lastZIndex &&
@@ -220,35 +426,58 @@ func (c *Context) end() {
 		c.bringToFront(c.nextHoverRoot)
 	}
 
+	// end panning once the middle mouse button is released
+	if (c.mouseDown & mouseMiddle) == 0 {
+		c.panTarget = nil
+	}
+
+	// end dragging once the mouse is released
+	if c.drag != nil && (c.mouseDown&mouseLeft) == 0 {
+		c.drag = nil
+	}
+	c.dropWindowTitle()
+
 	// reset input state
 	c.keyPressed = 0
 	c.textInput = nil
 	c.mousePressed = 0
+	c.mouseReleased = 0
+	c.doubleClicked = false
 	c.scrollDelta = image.Pt(0, 0)
 	c.lastMousePos = c.mousePos
 
-	// sort root containers by zindex
-	sort.SliceStable(c.rootList, func(i, j int) bool {
-		return c.rootList[i].ZIndex < c.rootList[j].ZIndex
-	})
+	// sort root containers by layer and zindex
+	c.sortRoots()
 
-	// set root container jump commands
-	for i := 0; i < len(c.rootList); i++ {
-		cnt := c.rootList[i]
+	// set root container jump commands, leaving out the offscreen windows
+	// drawn with DrawWindow and the windows drawn with DrawViewport
+	roots := c.rootList[:0:0]
+	for _, cnt := range c.rootList {
+		if !cnt.Offscreen && cnt.Viewport == 0 {
+			roots = append(roots, cnt)
+		}
+	}
+	if len(roots) == 0 && len(c.commandList) > 0 {
+		c.commandList[0].jump.dstIdx = len(c.commandList)
+	}
+	for i := 0; i < len(roots); i++ {
+		cnt := roots[i]
 		// if this is the first container then make the first command jump to it.
 		// otherwise set the previous container's tail to jump to this one
 		if i == 0 {
-			cmd := c.commandList[0]
+			cmd := &c.commandList[0]
 			expect(cmd.typ == commandJump)
 			cmd.jump.dstIdx = cnt.HeadIdx + 1
 			expect(cmd.jump.dstIdx < commandListSize)
 		} else {
-			prev := c.rootList[i-1]
+			prev := roots[i-1]
 			c.commandList[prev.TailIdx].jump.dstIdx = cnt.HeadIdx + 1
 		}
 		// make the last container's tail jump to the end of command list
-		if i == len(c.rootList)-1 {
+		if i == len(roots)-1 {
 			c.commandList[cnt.TailIdx].jump.dstIdx = len(c.commandList)
 		}
 	}
+
+	c.updateDirty()
 }
@@ -0,0 +1,40 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 The Ebitengine Authors
+
+package microui
+
+import "image"
+
+// SetComposition sets the text being composed with an input method, like the
+// state of Ebitengine's exp/textinput package. It is shown underlined at the
+// caret of the focused text box until it is committed with CommitComposition,
+// or cleared with an empty text.
+func (c *Context) SetComposition(text string) {
+	c.composition = text
+}
+
+// CommitComposition ends the composition, typing text in the focused text box
+// in the next frame.
+func (c *Context) CommitComposition(text string) {
+	c.composition = ""
+	c.virtualInput = append(c.virtualInput, []rune(text)...)
+}
+
+// TextCaretRect returns the area of the caret of the focused text box in the
+// last frame, where the candidates of an input method can be shown, and
+// whether a text box is focused.
+func (c *Context) TextCaretRect() (image.Rectangle, bool) {
+	if c.textEdit == 0 {
+		return image.Rectangle{}, false
+	}
+	return c.textCaretRect, true
+}
+
+// compositionText returns the text str with the composed text inserted at
+// caret, and the caret after it.
+func (c *Context) compositionText(str string, caret int) (string, int) {
+	if c.composition == "" {
+		return str, caret
+	}
+	return str[:caret] + c.composition + str[caret:], caret + len(c.composition)
+}
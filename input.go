@@ -9,6 +9,27 @@ import (
 	"github.com/hajimehoshi/ebiten/v2"
 )
 
+// InputState is the whole input of a frame: the cursor, the held buttons and
+// keys, the typed text and the wheel movement.
+type InputState struct {
+	// Cursor is the position of the cursor on the screen.
+	Cursor       image.Point
+	MouseButtons []ebiten.MouseButton
+	Keys         []ebiten.Key
+	Runes        []rune
+	// WheelX and WheelY are the movement of the wheel, as returned by
+	// ebiten.Wheel.
+	WheelX, WheelY float64
+}
+
+// SetInputState sets the input of the next frame, instead of the input read
+// from Ebitengine, like a recorded input being replayed, the input of a
+// remote client, or the input of a test. The presses and releases of the
+// buttons and keys are relative to the input of the previous frame.
+func (c *Context) SetInputState(s InputState) {
+	c.inputState = &s
+}
+
 func (c *Context) inputMouseMove(x, y int) {
 	c.mousePos = image.Pt(x, y)
 }
@@ -27,13 +48,40 @@ func mouseButtonToInt(btn ebiten.MouseButton) int {
 
 func (c *Context) inputMouseDown(x, y int, btn ebiten.MouseButton) {
 	c.inputMouseMove(x, y)
+	if btn == ebiten.MouseButtonLeft {
+		// detect double clicks, allowing the mouse to move slightly
+		p := c.mousePos.Sub(c.lastClickPos)
+		c.doubleClicked = c.now-c.lastClick <= c.Style.DoubleClickTime && p.X*p.X+p.Y*p.Y <= 16
+		c.lastClick = c.now
+		c.lastClickPos = c.mousePos
+		if c.doubleClicked {
+			// a third click starts a new double click
+			c.lastClick = -c.Style.DoubleClickTime
+		}
+	}
 	c.mouseDown |= mouseButtonToInt(btn)
 	c.mousePressed |= mouseButtonToInt(btn)
 }
 
+// clickReleased reports whether the left mouse button is released without the
+// mouse moving since it was pressed, ending a click rather than a drag.
+func (c *Context) clickReleased() bool {
+	return (c.mouseReleased&mouseLeft) != 0 && !c.clickMoved
+}
+
 func (c *Context) inputMouseUp(x, y int, btn ebiten.MouseButton) {
 	c.inputMouseMove(x, y)
 	c.mouseDown &= ^mouseButtonToInt(btn)
+	c.mouseReleased |= mouseButtonToInt(btn)
+}
+
+// wheelDistance returns the scroll distance in pixels for a wheel delta.
+func (c *Context) wheelDistance(delta float64) int {
+	d := -delta * c.Style.WheelScroll
+	if c.Style.WheelScrollLines {
+		d *= float64(lineHeight() + c.Style.Spacing)
+	}
+	return int(d)
 }
 
 func (c *Context) inputScroll(x, y int) {
@@ -53,6 +101,36 @@ func keyToInt(key ebiten.Key) int {
 		return keyBackspace
 	case ebiten.KeyEnter:
 		return keyReturn
+	case ebiten.KeyArrowLeft:
+		return keyLeft
+	case ebiten.KeyArrowRight:
+		return keyRight
+	case ebiten.KeyArrowUp:
+		return keyUp
+	case ebiten.KeyArrowDown:
+		return keyDown
+	case ebiten.KeyHome:
+		return keyHome
+	case ebiten.KeyEnd:
+		return keyEnd
+	case ebiten.KeyPageUp:
+		return keyPageUp
+	case ebiten.KeyPageDown:
+		return keyPageDown
+	case ebiten.KeyEscape:
+		return keyEscape
+	case ebiten.KeyTab:
+		return keyTab
+	case ebiten.KeyDelete:
+		return keyDelete
+	case ebiten.KeySpace:
+		return keySpace
+	case ebiten.KeyC:
+		return keyC
+	case ebiten.KeyV:
+		return keyV
+	case ebiten.KeyX:
+		return keyX
 	}
 	return 0
 }
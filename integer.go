@@ -0,0 +1,57 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 The Ebitengine Authors
+
+package microui
+
+import (
+	"math"
+	"unsafe"
+)
+
+// intFmt is the format of the integer controls.
+const intFmt = "%.0f"
+
+// intControl builds a number control editing value through a float64, kept
+// between frames so that drags finer than 1 add up, and reports the events of
+// the control with integer values.
+func (c *Context) intControl(value *int, f func(v *float64, id ID) Response) Response {
+	id := c.id(ptrToBytes(unsafe.Pointer(value)))
+	last := *value
+	v := WidgetState[float64](c.Widget(), id)
+	if c.focus != id || int(math.Round(*v)) != *value {
+		*v = float64(*value)
+	}
+
+	c.muteEvents++
+	res := f(v, id)
+	c.muteEvents--
+
+	*value = int(math.Round(*v))
+	res &^= ResponseChange
+	if *value != last {
+		res |= ResponseChange
+	}
+	if (res & ResponseChange) != 0 {
+		c.eventValues(last, *value)
+	} else if (res & ResponseSubmit) != 0 {
+		c.eventValues(*value, *value)
+	}
+	c.emitEvents(id, res)
+	return res
+}
+
+// NumberInt is like Number, for an integer value changed by steps of step.
+func (c *Context) NumberInt(value *int, step int) Response {
+	return c.intControl(value, func(v *float64, id ID) Response {
+		return c.number(v, id, float64(step), valueFormat{verb: intFmt}, OptAlignCenter)
+	})
+}
+
+// SliderInt is like SliderEx, for an integer value between low and high
+// changed by steps of step, or 1 if step is 0.
+func (c *Context) SliderInt(value *int, low, high, step int, opt Option) Response {
+	step = max(step, 1)
+	return c.intControl(value, func(v *float64, id ID) Response {
+		return c.slider(v, id, float64(low), float64(high), float64(step), nil, nil, valueFormat{verb: intFmt}, opt)
+	})
+}
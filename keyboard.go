@@ -0,0 +1,119 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 The Ebitengine Authors
+
+package microui
+
+import (
+	"image"
+	"strings"
+	"unicode"
+)
+
+// KeyboardLayout is the layout of a virtual keyboard: a row of keys per
+// item. A key is a string typed by the key, or one of the special keys
+// "Shift", "Back", "Enter" and "Space".
+type KeyboardLayout [][]string
+
+var (
+	// KeyboardQwerty is a QWERTY keyboard layout.
+	KeyboardQwerty = KeyboardLayout{
+		{"1", "2", "3", "4", "5", "6", "7", "8", "9", "0"},
+		{"q", "w", "e", "r", "t", "y", "u", "i", "o", "p"},
+		{"a", "s", "d", "f", "g", "h", "j", "k", "l", "Back"},
+		{"Shift", "z", "x", "c", "v", "b", "n", "m", ".", "Enter"},
+		{"Space"},
+	}
+	// KeyboardNumeric is a numeric keypad layout.
+	KeyboardNumeric = KeyboardLayout{
+		{"7", "8", "9"},
+		{"4", "5", "6"},
+		{"1", "2", "3"},
+		{"-", "0", "."},
+		{"Back", "Enter"},
+	}
+)
+
+// VirtualKeyboard is an on-screen keyboard with the given layout, typing in
+// the focused text box as a physical keyboard would, for the platforms
+// without one. The keys don't take the focus.
+func (c *Context) VirtualKeyboard(layout KeyboardLayout) {
+	// the keys type in the control focused before the keyboard is clicked
+	if c.focus != 0 {
+		c.keyboardTarget = c.focus
+	}
+	for _, row := range layout {
+		body := c.layout().body
+		n := len(row)
+		w := max((body.Dx()-c.Style.Spacing*(n-1))/n, 1)
+		widths := make([]int, n)
+		for i := range widths {
+			widths[i] = w
+		}
+		widths[n-1] = -1
+		c.SetLayoutRow(widths, 0)
+		for _, key := range row {
+			c.virtualKey(key)
+		}
+	}
+}
+
+// virtualKey is a key of a virtual keyboard.
+func (c *Context) virtualKey(key string) {
+	c.Control(0, 0, func(r image.Rectangle) Response {
+		label := key
+		switch {
+		case key == "Shift" || key == "Back" || key == "Enter" || key == "Space":
+			// the special keys are labeled in the language of the UI
+			label = c.tr(key)
+		case c.keyboardShift && len(key) == 1:
+			label = strings.ToUpper(key)
+		}
+		hovered := c.mouseOver(r)
+		colorid := ColorButton
+		if hovered {
+			colorid = ColorButtonHover
+		}
+		if (key == "Shift" && c.keyboardShift) || (hovered && (c.mouseDown&mouseLeft) != 0) {
+			colorid = ColorButtonFocus
+		}
+		c.drawFrame(r, colorid)
+		c.drawControlText(label, r, ColorText, OptAlignCenter)
+
+		if !hovered || c.mousePressed != mouseLeft {
+			return 0
+		}
+		// consume the click so that it doesn't move the focus
+		c.mousePressed = 0
+		c.SetFocus(c.keyboardTarget)
+		switch key {
+		case "Shift":
+			c.keyboardShift = !c.keyboardShift
+		case "Back":
+			c.virtualKeys |= keyBackspace
+		case "Enter":
+			c.virtualKeys |= keyReturn
+		case "Space":
+			c.virtualInput = append(c.virtualInput, ' ')
+		default:
+			for _, r := range key {
+				if c.keyboardShift {
+					r = unicode.ToUpper(r)
+				}
+				c.virtualInput = append(c.virtualInput, r)
+			}
+			c.keyboardShift = false
+		}
+		return ResponseSubmit
+	})
+}
+
+// applyVirtualKeys adds the keys pressed on a virtual keyboard in the last
+// frame to the input.
+func (c *Context) applyVirtualKeys() {
+	if len(c.virtualInput) > 0 {
+		c.inputText(append(c.textInput, c.virtualInput...))
+		c.virtualInput = nil
+	}
+	c.keyPressed |= c.virtualKeys
+	c.virtualKeys = 0
+}
@@ -0,0 +1,116 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 The Ebitengine Authors
+
+package microui
+
+import "slices"
+
+// Layer is a band of windows: the windows of a layer are always in front of
+// the windows of the lower layers, whatever their z-index.
+type Layer int
+
+const (
+	// LayerBackground is for the windows behind the others, like a HUD.
+	LayerBackground Layer = iota - 1
+	// LayerNormal is the layer of the windows by default.
+	LayerNormal
+	// LayerModal is for dialogs. The modal windows are in this layer unless
+	// they are in a higher one.
+	LayerModal
+	// LayerOverlay is for the windows in front of everything, like tooltips
+	// and drag previews.
+	LayerOverlay
+)
+
+// behind reports whether the window a is behind the window b.
+func behind(a, b *Container) bool {
+	if a.Layer != b.Layer {
+		return a.Layer < b.Layer
+	}
+	return a.ZIndex < b.ZIndex
+}
+
+// popupLayer returns the layer of a popup opened from the window opener, or
+// outside of any window if opener is nil: the layer of its opener, and at
+// least LayerModal while a modal window is open, so that it is in front of
+// them.
+func (c *Context) popupLayer(opener *Container) Layer {
+	l := LayerNormal
+	if opener != nil {
+		l = opener.Layer
+	}
+	if (c.modal != nil || c.nextModal != nil) && l < LayerModal {
+		l = LayerModal
+	}
+	return l
+}
+
+// compareZ compares the windows a and b by layer and z-index.
+func compareZ(a, b *Container) int {
+	switch {
+	case behind(a, b):
+		return -1
+	case behind(b, a):
+		return 1
+	}
+	return 0
+}
+
+// zIndexLimit is the z-index at which the z-indices are renumbered, so that
+// they never overflow in a long running application.
+const zIndexLimit = 1 << 30
+
+func (c *Context) bringToFront(cnt *Container) {
+	if c.lastZIndex >= zIndexLimit {
+		c.renumberZIndices()
+	}
+	c.lastZIndex++
+	cnt.ZIndex = c.lastZIndex
+
+	// move cnt to its new place in the z-order
+	c.zOrder = slices.DeleteFunc(c.zOrder, func(m *Container) bool {
+		return m == cnt
+	})
+	i := slices.IndexFunc(c.zOrder, func(m *Container) bool {
+		return behind(cnt, m)
+	})
+	if i < 0 {
+		i = len(c.zOrder)
+	}
+	c.zOrder = slices.Insert(c.zOrder, i, cnt)
+}
+
+// renumberZIndices sets the z-indices of the containers to their position in
+// the z-order.
+func (c *Context) renumberZIndices() {
+	c.sortZOrder()
+	for i, cnt := range c.zOrder {
+		cnt.ZIndex = i + 1
+	}
+	c.lastZIndex = len(c.zOrder)
+}
+
+// sortZOrder sorts the containers back to front, if their layer or z-index
+// was changed other than with bringToFront.
+func (c *Context) sortZOrder() {
+	if !slices.IsSortedFunc(c.zOrder, compareZ) {
+		slices.SortStableFunc(c.zOrder, compareZ)
+	}
+}
+
+// sortRoots sorts the root containers back to front, in the z-order kept by
+// bringToFront, instead of sorting them again every frame.
+func (c *Context) sortRoots() {
+	c.sortZOrder()
+	for _, cnt := range c.rootList {
+		cnt.rooted = true
+	}
+	roots := c.rootList[:0]
+	for _, cnt := range c.zOrder {
+		if cnt.rooted {
+			cnt.rooted = false
+			roots = append(roots, cnt)
+		}
+	}
+	c.rootList = roots
+}
@@ -0,0 +1,58 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 The Ebitengine Authors
+
+package microui
+
+import (
+	"fmt"
+	"time"
+)
+
+// Localizer translates the strings shown by the package, like the labels of
+// the built-in buttons, and formats numbers and dates.
+type Localizer interface {
+	// Text returns the translation of the English string s.
+	Text(s string) string
+	// Plural returns the translation of the English format one if n is 1, or
+	// other otherwise, for the languages with different plural rules. The
+	// formats take n as argument.
+	Plural(n int, one, other string) string
+	// Number formats value with the printf format, like "%.2f".
+	Number(format string, value float64) string
+	// Time formats t.
+	Time(t time.Time) string
+}
+
+// englishLocalizer is the default localizer, leaving the strings as they
+// are.
+type englishLocalizer struct{}
+
+func (englishLocalizer) Text(s string) string {
+	return s
+}
+
+func (englishLocalizer) Plural(n int, one, other string) string {
+	if n == 1 {
+		return one
+	}
+	return other
+}
+
+func (englishLocalizer) Number(format string, value float64) string {
+	return fmt.Sprintf(format, value)
+}
+
+func (englishLocalizer) Time(t time.Time) string {
+	return t.Format(time.DateTime)
+}
+
+// tr returns the translation of the built-in string s.
+func (c *Context) tr(s string) string {
+	return c.Localizer.Text(s)
+}
+
+// LabelPlural is a label showing n with the translated plural form of the
+// English formats one and other, like "%d item" and "%d items".
+func (c *Context) LabelPlural(n int, one, other string) {
+	c.Label(fmt.Sprintf(c.Localizer.Plural(n, one, other), n))
+}
@@ -0,0 +1,50 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 The Ebitengine Authors
+
+package microui
+
+import (
+	"image"
+	"strings"
+)
+
+// MeasureText returns the size of s drawn with the font of the UI, with a
+// line per line of s.
+func (c *Context) MeasureText(s string) image.Point {
+	var size image.Point
+	for _, line := range strings.Split(s, "\n") {
+		size.X = max(size.X, textWidth(line))
+		size.Y += lineHeight()
+	}
+	return size
+}
+
+// LineHeight returns the height of a line of text drawn with the font of the
+// UI.
+func (c *Context) LineHeight() int {
+	return lineHeight()
+}
+
+// ControlHeight returns the height of a control in a row with the default
+// height.
+func (c *Context) ControlHeight() int {
+	return c.Style.Size.Y + c.Style.Padding*2
+}
+
+// LabelSize returns the size of a label or a button showing text without
+// truncating it.
+func (c *Context) LabelSize(text string) image.Point {
+	return image.Pt(textWidth(text)+c.Style.Padding*2, c.ControlHeight())
+}
+
+// CheckboxSize returns the size of a check box with label.
+func (c *Context) CheckboxSize(label string) image.Point {
+	h := c.ControlHeight()
+	return image.Pt(h+textWidth(label)+c.Style.Padding*2, h)
+}
+
+// WindowSize returns the size of a window whose body is content wide and
+// high, with the title bar and the padding.
+func (c *Context) WindowSize(content image.Point) image.Point {
+	return image.Pt(content.X+c.Style.Padding*2, content.Y+c.Style.Padding*2+c.Style.TitleHeight)
+}
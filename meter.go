@@ -0,0 +1,72 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 The Ebitengine Authors
+
+package microui
+
+import (
+	"image"
+	"image/color"
+)
+
+// MeterZone is a zone of a level meter, from From to the From of the next
+// zone, drawn with Color.
+type MeterZone struct {
+	From  float64
+	Color color.RGBA
+}
+
+// DefaultMeterZones are the zones of Meter: green, then yellow from 0.7 and
+// red from 0.9.
+var DefaultMeterZones = []MeterZone{
+	{From: 0, Color: color.RGBA{60, 180, 75, 255}},
+	{From: 0.7, Color: color.RGBA{230, 200, 40, 255}},
+	{From: 0.9, Color: color.RGBA{220, 50, 50, 255}},
+}
+
+// Meter is a horizontal level meter, like a VU meter, showing level and a
+// peak mark at peak, both between 0 and 1.
+func (c *Context) Meter(level, peak float64) {
+	c.MeterEx(level, peak, DefaultMeterZones, 0)
+}
+
+// MeterEx is like Meter with the zones zones, sorted by From. With
+// OptVertical, the meter goes from the bottom to the top.
+func (c *Context) MeterEx(level, peak float64, zones []MeterZone, opt Option) {
+	c.Control(0, 0, func(r image.Rectangle) Response {
+		vertical := (opt & OptVertical) != 0
+		// part returns the part of r from a to b
+		part := func(a, b float64) image.Rectangle {
+			a, b = clampF(a, 0, 1), clampF(b, 0, 1)
+			if vertical {
+				return image.Rect(r.Min.X, r.Max.Y-int(b*float64(r.Dy())), r.Max.X, r.Max.Y-int(a*float64(r.Dy())))
+			}
+			return image.Rect(r.Min.X+int(a*float64(r.Dx())), r.Min.Y, r.Min.X+int(b*float64(r.Dx())), r.Max.Y)
+		}
+
+		c.drawFrame(r, ColorBase)
+		peakColor := c.Style.Colors[ColorText]
+		for i, z := range zones {
+			to := 1.0
+			if i+1 < len(zones) {
+				to = zones[i+1].From
+			}
+			// the zones above the level are empty
+			if z.From < level {
+				c.drawRect(part(z.From, minF(to, level)), z.Color)
+			}
+			if peak >= z.From {
+				peakColor = z.Color
+			}
+		}
+		if peak > 0 {
+			p := part(peak, peak)
+			if vertical {
+				p.Min.Y -= 2
+			} else {
+				p.Max.X += 2
+			}
+			c.drawRect(p.Intersect(r), peakColor)
+		}
+		return 0
+	})
+}
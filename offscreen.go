@@ -0,0 +1,126 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 The Ebitengine Authors
+
+package microui
+
+import (
+	"image"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// SetWindowCursor sets the cursor of the offscreen window named name to pos,
+// in the space of its image, like the point of the image hit by a ray. in
+// reports whether the cursor is on the image at all.
+func (c *Context) SetWindowCursor(name string, pos image.Point, in bool) {
+	cnt := c.windowContainer(name)
+	cnt.cursor = pos
+	cnt.cursorIn = in
+}
+
+// DrawWindow draws the offscreen window named name to dst, if it was built
+// in the last frame. The transform of the context is not applied.
+func (c *Context) DrawWindow(name string, dst *ebiten.Image) {
+	var cnt *Container
+	for _, r := range c.rootList {
+		if r.name == name && r.Offscreen {
+			cnt = r
+			break
+		}
+	}
+	if cnt == nil {
+		return
+	}
+
+	// the popups opened from the window are drawn with it, in z-order
+	r := renderer{screen: dst, target: dst}
+	for _, root := range c.rootList {
+		if root == cnt || (root.owner == cnt && root.Offscreen) {
+			c.drawContainer(&r, root)
+		}
+	}
+	r.flush()
+}
+
+// inheritTarget makes the window cnt opened from the window opener, like a
+// popup or a tooltip, drawn where opener is drawn: to the image of an
+// offscreen window, in a viewport, or on the screen if opener is nil.
+func inheritTarget(cnt, opener *Container) {
+	cnt.Offscreen, cnt.Viewport, cnt.owner = false, 0, nil
+	if opener == nil {
+		return
+	}
+	cnt.Viewport = opener.Viewport
+	if opener.Offscreen {
+		cnt.Offscreen = true
+		cnt.owner = opener
+		if opener.owner != nil {
+			cnt.owner = opener.owner
+		}
+	}
+}
+
+// targetCursor returns the position of the mouse in the space the window cnt
+// is drawn to, outside of any window being built.
+func (c *Context) targetCursor(cnt *Container) image.Point {
+	switch {
+	case cnt.Offscreen:
+		src := cnt
+		if cnt.owner != nil {
+			src = cnt.owner
+		}
+		if !src.cursorIn {
+			return outside
+		}
+		return src.cursor
+	case cnt.Viewport != 0:
+		t := c.viewports[cnt.Viewport].transform()
+		return image.Pt(t.invert(c.screenCursor.X, c.screenCursor.Y))
+	}
+	return c.mousePos
+}
+
+// drawContainer draws the commands of the root container cnt with r.
+func (c *Context) drawContainer(r *renderer, cnt *Container) {
+	for idx := cnt.HeadIdx + 1; idx < cnt.TailIdx; {
+		cmd := &c.commandList[idx]
+		if cmd.typ == commandJump {
+			idx = cmd.jump.dstIdx
+			continue
+		}
+		r.draw(cmd)
+		idx++
+	}
+}
+
+// outside is a mouse position outside of any window.
+var outside = image.Pt(-1<<30, -1<<30)
+
+// windowCursor returns the position and the move of the mouse in the space of
+// the window cnt, if it is offscreen or in a viewport.
+func (c *Context) windowCursor(cnt *Container) (image.Point, image.Point, bool) {
+	switch {
+	case cnt.Offscreen:
+		// a popup of an offscreen window has the cursor of the window
+		src := cnt
+		if cnt.owner != nil {
+			src = cnt.owner
+		}
+		last := cnt.lastCursor
+		cnt.lastCursor = src.cursor
+		if !src.cursorIn {
+			return outside, image.Point{}, true
+		}
+		return src.cursor, src.cursor.Sub(last), true
+	case cnt.Viewport != 0:
+		vp := c.viewports[cnt.Viewport]
+		if !c.screenCursor.In(vp.Bounds) {
+			return outside, image.Point{}, true
+		}
+		t := vp.transform()
+		pos := image.Pt(t.invert(c.screenCursor.X, c.screenCursor.Y))
+		last := image.Pt(t.invert(c.lastScreenCursor.X, c.lastScreenCursor.Y))
+		return pos, pos.Sub(last), true
+	}
+	return image.Point{}, image.Point{}, false
+}
@@ -3,19 +3,110 @@
 
 package microui
 
+// PoolOptions configures the pools retaining the state of the containers,
+// tree nodes and controls across frames.
+type PoolOptions struct {
+	// Containers, TreeNodes and Storages are the capacities of the pools.
+	// Zero means the default capacity. Storages is the number of states of
+	// controls, from WidgetState or State, retained before the states of the
+	// controls not built in the current frame are evicted.
+	Containers int
+	TreeNodes  int
+	Storages   int
+
+	// OnEvict is called with the ID of an item whose state is discarded,
+	// as the least recently updated unpinned item of a full pool, to make
+	// room for another item.
+	OnEvict func(id ID)
+}
+
+func (c *Context) initPools(opts *PoolOptions) {
+	containers := containerPoolSize
+	treeNodes := treeNodePoolSize
+	storages := storagePoolSize
+	if opts != nil {
+		if opts.Containers > 0 {
+			containers = opts.Containers
+		}
+		if opts.TreeNodes > 0 {
+			treeNodes = opts.TreeNodes
+		}
+		if opts.Storages > 0 {
+			storages = opts.Storages
+		}
+		c.onEvict = opts.OnEvict
+	}
+	c.containerPool = make([]poolItem, containers)
+	c.containers = make([]Container, containers)
+	c.zOrder = make([]*Container, containers)
+	for i := range c.containers {
+		c.zOrder[i] = &c.containers[i]
+	}
+	c.treeNodePool = make([]poolItem, treeNodes)
+	c.stateLimit = storages
+}
+
+// ContainerID returns the ID a container or window with the given name gets
+// when it is built at this point.
+func (c *Context) ContainerID(name string) ID {
+	// the containers are identified like the tree nodes
+	return c.TreeNodeID(name)
+}
+
+// SetPinned pins or unpins the state of the container, tree node or control
+// id. The state of a pinned item is not evicted from its pool, even if it is
+// not updated for a long time. When every item of a pool is pinned, a new
+// tree node isn't retained, a new container reuses the least recently
+// updated container, and the states of the controls exceed the capacity of
+// their pool.
+func (c *Context) SetPinned(id ID, pinned bool) {
+	if !pinned {
+		delete(c.pinned, id)
+		return
+	}
+	if c.pinned == nil {
+		c.pinned = map[ID]struct{}{}
+	}
+	c.pinned[id] = struct{}{}
+}
+
+// poolInit puts id in the slot of the least recently updated unpinned item of
+// the pool, and returns its index, or -1 if every item is pinned or was
+// updated this frame.
 func (c *Context) poolInit(items []poolItem, id ID) int {
+	n := c.poolLRU(items, true)
+	if n < 0 {
+		return -1
+	}
+	c.poolSet(items, n, id)
+	return n
+}
+
+// poolLRU returns the index of the least recently updated item of the pool,
+// leaving out the pinned items if unpinned is true, or -1 if there is none
+// older than this frame.
+func (c *Context) poolLRU(items []poolItem, unpinned bool) int {
 	f := c.tick
 	n := -1
 	for i := 0; i < len(items); i++ {
+		if _, ok := c.pinned[items[i].id]; ok && unpinned {
+			continue
+		}
 		if items[i].lastUpdate < f {
 			f = items[i].lastUpdate
 			n = i
 		}
 	}
-	expect(n > -1)
+	return n
+}
+
+// poolSet evicts the item n of the pool, if any, and puts id in its slot.
+func (c *Context) poolSet(items []poolItem, n int, id ID) {
+	if items[n].id != 0 && c.onEvict != nil {
+		c.onEvict(items[n].id)
+	}
 	items[n].id = id
 	c.poolUpdate(items, n)
-	return n
 }
 
 // returns the index of an ID in the pool. returns -1 if it is not found
@@ -0,0 +1,91 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 The Ebitengine Authors
+
+package microui
+
+import (
+	"fmt"
+	"image"
+	"sort"
+	"time"
+)
+
+// ProfileEntry is the cost of building a window or a control in the last
+// frame.
+type ProfileEntry struct {
+	// Name is the title of the window, or empty for a control.
+	Name string
+	ID   ID
+
+	// BuildTime is the time spent building the window or the control,
+	// including its children.
+	BuildTime time.Duration
+	// Commands is the number of draw commands added by the window or the
+	// control, including its children.
+	Commands int
+}
+
+// SetProfiling enables or disables the recording of the build time and the
+// command count of every window, and also of every control with an ID if
+// controls is true.
+func (c *Context) SetProfiling(enabled, controls bool) {
+	c.profiling = enabled
+	c.profileControls = enabled && controls
+	if !enabled {
+		c.profile = nil
+		c.nextProfile = nil
+	}
+}
+
+// Profile returns the entries recorded in the last frame while profiling is
+// enabled, from the most to the least expensive to build.
+func (c *Context) Profile() []ProfileEntry {
+	return c.profile
+}
+
+// profileStart returns a function recording the cost of building name or id
+// since the call to profileStart.
+func (c *Context) profileStart(name string, id ID) func() {
+	start := time.Now()
+	cmds := len(c.commandList)
+	return func() {
+		c.nextProfile = append(c.nextProfile, ProfileEntry{
+			Name:      name,
+			ID:        id,
+			BuildTime: time.Since(start),
+			Commands:  len(c.commandList) - cmds,
+		})
+	}
+}
+
+// swapProfile makes the entries recorded this frame the result of Profile.
+func (c *Context) swapProfile() {
+	if !c.profiling {
+		return
+	}
+	sort.SliceStable(c.nextProfile, func(i, j int) bool {
+		return c.nextProfile[i].BuildTime > c.nextProfile[j].BuildTime
+	})
+	c.profile, c.nextProfile = c.nextProfile, c.profile[:0]
+}
+
+// MetricsWindow builds a window at rect listing the windows and controls
+// recorded in the last frame, from the most to the least expensive to build.
+// Profiling must be enabled with SetProfiling.
+func (c *Context) MetricsWindow(rect image.Rectangle) {
+	c.Window(c.tr("Metrics"), rect, func(res Response) {
+		c.SetLayoutRow([]int{-140, 80, -1}, 0)
+		c.Label(c.tr("Name"))
+		c.Label(c.tr("Time"))
+		c.Label(c.tr("Commands"))
+		for _, e := range c.profile {
+			name := e.Name
+			if name == "" {
+				name = fmt.Sprintf(c.tr("control %016x"), uint64(e.ID))
+			}
+			c.LabelEx(name, OptEllipsis)
+			c.Label(e.BuildTime.String())
+			c.Label(fmt.Sprint(e.Commands))
+		}
+	})
+}
@@ -0,0 +1,57 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 The Ebitengine Authors
+
+package microui
+
+import "slices"
+
+// NeedsRedraw reports whether the last frame draws something different from
+// the frame before, so that the drawing can be skipped on idle frames, when
+// the screen is not cleared every frame (see
+// ebiten.SetScreenClearedEveryFrame). It is always true for a frame with
+// custom draw commands of DrawControl.
+func (c *Context) NeedsRedraw() bool {
+	return c.dirty
+}
+
+// updateDirty compares the commands of the frame to the commands of the last
+// frame, and keeps them for the next frame.
+func (c *Context) updateDirty() {
+	c.dirty = c.redraw || !sameCommands(c.commandList, c.lastCommands)
+	c.redraw = false
+	c.lastCommands = append(c.lastCommands[:0], c.commandList...)
+}
+
+func sameCommands(a, b []command) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		x, y := &a[i], &b[i]
+		if x.typ != y.typ {
+			return false
+		}
+		var same bool
+		switch x.typ {
+		case commandJump:
+			same = x.jump == y.jump
+		case commandClip:
+			same = x.clip == y.clip
+		case commandRect:
+			same = x.rect == y.rect
+		case commandText:
+			same = x.text == y.text
+		case commandIcon:
+			same = x.icon == y.icon
+		case commandTransform:
+			same = x.transform == y.transform
+		case commandPolyline:
+			same = x.polyline.color == y.polyline.color && x.polyline.width == y.polyline.width &&
+				slices.Equal(x.polyline.points, y.polyline.points)
+		}
+		if !same {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,69 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 The Ebitengine Authors
+
+package microui
+
+import (
+	"image"
+	"math"
+	"unsafe"
+)
+
+// SearchBox is a text box for a filter query, after a magnifier icon and
+// followed by a button clearing the query, filling the next cell of the
+// layout. It reports ResponseChange when the query changes.
+func (c *Context) SearchBox(query *string) Response {
+	c.pushID(ptrToBytes(unsafe.Pointer(query)))
+	defer c.popID()
+
+	var res Response
+	c.LayoutColumn(func() {
+		h := c.ControlHeight()
+		c.SetLayoutRow([]int{h, -(h + c.Style.Spacing), -1}, 0)
+		c.Control(0, 0, func(r image.Rectangle) Response {
+			c.drawMagnifier(r)
+			return 0
+		})
+		textID := c.id(ptrToBytes(unsafe.Pointer(query)))
+		res = c.textBoxRaw(query, textID, textBoxConfig{}, 0)
+
+		clearID := c.id([]byte("!clear"))
+		res |= c.Control(clearID, 0, func(r image.Rectangle) Response {
+			var res Response
+			if c.activated(clearID) && *query != "" {
+				c.eventValues(*query, "")
+				*query = ""
+				res |= ResponseChange
+				c.SetFocus(textID)
+			}
+			if *query != "" {
+				if c.hover == clearID {
+					c.drawFrame(r, ColorButtonHover)
+				}
+				c.drawIcon(iconClose, r, c.Style.Colors[ColorText])
+			}
+			return res
+		})
+	})
+	return res
+}
+
+// drawMagnifier draws a magnifier icon in r.
+func (c *Context) drawMagnifier(r image.Rectangle) {
+	clr := c.Style.Colors[ColorText]
+	s := float64(min(r.Dx(), r.Dy()))
+	cx, cy := float64(r.Min.X)+s*0.45, float64(r.Min.Y)+s*0.45
+	rad := s * 0.2
+	const n = 16
+	points := make([]image.Point, 0, n+1)
+	for i := 0; i <= n; i++ {
+		a := 2 * math.Pi * float64(i) / n
+		points = append(points, image.Pt(int(math.Round(cx+rad*math.Cos(a))), int(math.Round(cy+rad*math.Sin(a)))))
+	}
+	c.DrawPolyline(points, clr, 1.5)
+	d := rad * math.Sqrt2 / 2
+	c.DrawPolyline([]image.Point{
+		image.Pt(int(cx+d), int(cy+d)),
+		image.Pt(int(cx+d+s*0.2), int(cy+d+s*0.2)),
+	}, clr, 2)
+}
@@ -0,0 +1,44 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 The Ebitengine Authors
+
+package microui
+
+import (
+	"image"
+	"strconv"
+	"unsafe"
+)
+
+// Segmented is a bar of joined toggle segments, one per option, filling the
+// next cell of the layout, like a switch between view modes. Selecting a
+// segment sets selected to its index.
+func (c *Context) Segmented(options []string, selected *int) Response {
+	id := c.id(ptrToBytes(unsafe.Pointer(selected)))
+	c.pushID(ptrToBytes(unsafe.Pointer(selected)))
+	defer c.popID()
+	return c.Control(id, OptNoInteract, func(r image.Rectangle) Response {
+		var res Response
+		n := len(options)
+		for i, option := range options {
+			seg := image.Rect(r.Min.X+r.Dx()*i/n, r.Min.Y, r.Min.X+r.Dx()*(i+1)/n, r.Max.Y)
+			segID := c.id([]byte("!segment" + strconv.Itoa(i)))
+			c.addTabStop(segID)
+			c.updateControl(segID, seg, 0)
+			if c.activated(segID) && *selected != i {
+				c.eventValues(*selected, i)
+				*selected = i
+				res |= ResponseChange
+			}
+
+			colorid := ColorButton
+			if i == *selected {
+				colorid = ColorButtonFocus
+			} else if c.hover == segID {
+				colorid = ColorButtonHover
+			}
+			c.drawFrame(seg, colorid)
+			c.drawControlText(option, seg, ColorText, OptAlignCenter)
+		}
+		return res
+	})
+}
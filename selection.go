@@ -0,0 +1,77 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 The Ebitengine Authors
+
+package microui
+
+import (
+	"image"
+)
+
+// textSelection is a range of selected text, between the positions anchor
+// and caret in bytes.
+type textSelection struct {
+	anchor, caret int
+}
+
+// bounds returns the selected range, in order.
+func (s textSelection) bounds() (int, int) {
+	return min(s.anchor, s.caret), max(s.anchor, s.caret)
+}
+
+// copyPressed reports whether Control+C is pressed.
+func (c *Context) copyPressed() bool {
+	return (c.keyDown&keyControl) != 0 && (c.keyPressed&keyC) != 0
+}
+
+// drawTextSelection draws the highlight of the range [start, end) of str drawn
+// at pos with the paragraph direction dir.
+func (c *Context) drawTextSelection(str string, pos image.Point, start, end int, dir int) {
+	if start == end {
+		return
+	}
+	x0, x1 := caretX(str, start, dir), caretX(str, end, dir)
+	c.drawRect(image.Rect(pos.X+min(x0, x1), pos.Y, pos.X+max(x0, x1), pos.Y+lineHeight()), c.Style.Colors[ColorTextSelection])
+}
+
+// SelectableText is a read-only line of text which can be selected with the
+// mouse and copied with Control+C, like an error message or a file path. A
+// double click selects the whole text, and copying with nothing selected
+// copies the whole text.
+func (c *Context) SelectableText(text string) {
+	id := c.id([]byte("!selectable" + text))
+	c.Control(id, OptHoldFocus, func(r image.Rectangle) Response {
+		s := WidgetState[textSelection](c.Widget(), id)
+		s.anchor = runeStart(text, s.anchor)
+		s.caret = runeStart(text, s.caret)
+		pos := image.Pt(r.Min.X+c.Style.Padding, r.Min.Y+(r.Dy()-lineHeight())/2)
+		dir := c.controlTextDir(0)
+
+		if c.focus == id {
+			i := textIndexAt(text, c.mousePos.X-pos.X, dir)
+			switch {
+			case c.mousePressed == mouseLeft && c.doubleClicked:
+				s.anchor, s.caret = 0, len(text)
+			case c.mousePressed == mouseLeft:
+				s.anchor, s.caret = i, i
+			case c.mouseDown == mouseLeft:
+				s.caret = i
+			}
+			if c.copyPressed() {
+				start, end := s.bounds()
+				if start == end {
+					start, end = 0, len(text)
+				}
+				c.Clipboard.SetText(text[start:end])
+			}
+		}
+
+		c.pushClipRect(r)
+		defer c.popClipRect()
+		if c.focus == id {
+			start, end := s.bounds()
+			c.drawTextSelection(text, pos, start, end, dir)
+		}
+		c.drawTextDir(text, pos, c.Style.Colors[ColorText], dir)
+		return 0
+	})
+}
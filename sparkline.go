@@ -0,0 +1,41 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 The Ebitengine Authors
+
+package microui
+
+import (
+	"image"
+	"math"
+)
+
+// Sparkline is a small line chart of values, scaled to their range, fitting
+// in a cell of a table or a row of labels. The last value is marked.
+func (c *Context) Sparkline(values []float64) {
+	c.Control(0, 0, func(r image.Rectangle) Response {
+		if len(values) == 0 {
+			return 0
+		}
+		lo, hi := math.Inf(1), math.Inf(-1)
+		for _, v := range values {
+			lo, hi = minF(lo, v), maxF(hi, v)
+		}
+		r = r.Inset(c.Style.Padding / 2)
+		points := make([]image.Point, len(values))
+		for i, v := range values {
+			x := r.Min.X
+			if len(values) > 1 {
+				x += i * (r.Dx() - 1) / (len(values) - 1)
+			}
+			y := (r.Min.Y + r.Max.Y) / 2
+			if hi > lo {
+				y = r.Max.Y - 1 - int((v-lo)/(hi-lo)*float64(r.Dy()-1))
+			}
+			points[i] = image.Pt(x, y)
+		}
+		clr := c.Style.Colors[ColorText]
+		c.DrawPolyline(points, clr, 1)
+		last := points[len(points)-1]
+		c.drawRect(image.Rect(last.X-1, last.Y-1, last.X+2, last.Y+2), c.Style.Colors[ColorHighlight])
+		return 0
+	})
+}
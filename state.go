@@ -0,0 +1,173 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 The Ebitengine Authors
+
+package microui
+
+import (
+	"reflect"
+	"slices"
+)
+
+// UIState is a snapshot of the state retained by a context between frames,
+// taken with SnapshotState.
+type UIState struct {
+	hover      ID
+	focus      ID
+	focusByKey bool
+
+	containerPool []poolItem
+	containers    []Container
+	treeNodePool  []poolItem
+
+	hoverRoot int
+	modal     int
+
+	numberEdit    ID
+	numberEditBuf string
+	textEdit      ID
+	textEditOrig  string
+	textCaret     int
+	textAnchor    int
+	textScroll    *int
+	editor        *editorState
+}
+
+// containerIndex returns the index of cnt in the container pool, or -1 if cnt
+// is nil.
+func (c *Context) containerIndex(cnt *Container) int {
+	for i := range c.containers {
+		if &c.containers[i] == cnt {
+			return i
+		}
+	}
+	return -1
+}
+
+// containerAt returns the container at idx in the container pool, or nil if
+// idx is -1.
+func (c *Context) containerAt(idx int) *Container {
+	if idx < 0 || idx >= len(c.containers) {
+		return nil
+	}
+	return &c.containers[idx]
+}
+
+// SnapshotState returns the state retained by the context: the focused and
+// hovered controls, the windows with their position, scroll and open state,
+// the expanded tree nodes and headers, and the text being edited with its
+// caret, selection and scroll. It must be called outside of Begin and End.
+//
+// The state can be restored with RestoreState, for example to switch between
+// workspaces, or to go back to a previous state while debugging a UI flow.
+func (c *Context) SnapshotState() *UIState {
+	containers := slices.Clone(c.containers)
+	relinkContainers(containers, c.containers)
+	return &UIState{
+		hover:         c.hover,
+		focus:         c.focus,
+		focusByKey:    c.focusByKey,
+		containerPool: slices.Clone(c.containerPool),
+		containers:    containers,
+		treeNodePool:  slices.Clone(c.treeNodePool),
+		hoverRoot:     c.containerIndex(c.hoverRoot),
+		modal:         c.containerIndex(c.modal),
+		numberEdit:    c.numberEdit,
+		numberEditBuf: c.numberEditBuf,
+		textEdit:      c.textEdit,
+		textEditOrig:  c.textEditOrig,
+		textCaret:     c.textCaret,
+		textAnchor:    c.textAnchor,
+		textScroll:    cloneWidgetState[int](c, fnv1a(c.focus, []byte("!scroll"))),
+		editor:        cloneWidgetState[editorState](c, c.focus),
+	}
+}
+
+// relinkContainers makes the containers cnts, copied from the containers
+// from, independent of them: the links between the containers point to the
+// containers at the same index in cnts, and the tabs and the style are
+// copied.
+func relinkContainers(cnts, from []Container) {
+	at := func(p *Container) *Container {
+		for i := range from {
+			if &from[i] == p {
+				return &cnts[i]
+			}
+		}
+		return nil
+	}
+	for i := range cnts {
+		cnt := &cnts[i]
+		cnt.tabHost = at(cnt.tabHost)
+		cnt.activeTab = at(cnt.activeTab)
+		cnt.owner = at(cnt.owner)
+		if cnt.tabs != nil {
+			tabs := make([]*Container, len(cnt.tabs))
+			for j, t := range cnt.tabs {
+				tabs[j] = at(t)
+			}
+			cnt.tabs = tabs
+		}
+		if cnt.Style != nil {
+			st := *cnt.Style
+			cnt.Style = &st
+		}
+	}
+}
+
+// cloneWidgetState returns a copy of the state of type T of the control id,
+// or nil if it has none.
+func cloneWidgetState[T any](c *Context, id ID) *T {
+	s, ok := c.widgetStates[widgetKey{id: id, typ: reflect.TypeFor[T]()}]
+	if !ok {
+		return nil
+	}
+	v := *s.value.(*T)
+	return &v
+}
+
+// RestoreState restores the state taken with SnapshotState. It must be
+// called outside of Begin and End, with a state taken from a context with the
+// same pool sizes.
+func (c *Context) RestoreState(s *UIState) {
+	expect(len(s.containers) == len(c.containers) && len(s.treeNodePool) == len(c.treeNodePool))
+
+	// the containers are restored in place, so that they are still referenced
+	// by the same pointers
+	copy(c.containerPool, s.containerPool)
+	copy(c.containers, s.containers)
+	relinkContainers(c.containers, s.containers)
+	copy(c.treeNodePool, s.treeNodePool)
+	// the restored items are not evicted before being used again
+	for _, items := range [][]poolItem{c.containerPool, c.treeNodePool} {
+		for i := range items {
+			if items[i].id != 0 {
+				items[i].lastUpdate = c.tick
+			}
+		}
+	}
+
+	c.hover = s.hover
+	c.focus = s.focus
+	c.focusByKey = s.focusByKey
+	c.hoverRoot = c.containerAt(s.hoverRoot)
+	c.nextHoverRoot = nil
+	c.modal = c.containerAt(s.modal)
+	c.nextModal = nil
+	c.scrollTarget = nil
+	c.panTarget = nil
+	c.focusContainer = nil
+	c.drag = nil
+
+	c.numberEdit = s.numberEdit
+	c.numberEditBuf = s.numberEditBuf
+	c.textEdit = s.textEdit
+	c.textEditOrig = s.textEditOrig
+	c.textCaret = s.textCaret
+	c.textAnchor = s.textAnchor
+	if s.textScroll != nil {
+		*WidgetState[int](c.Widget(), fnv1a(s.focus, []byte("!scroll"))) = *s.textScroll
+	}
+	if s.editor != nil {
+		*WidgetState[editorState](c.Widget(), s.focus) = *s.editor
+	}
+}
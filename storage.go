@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 The Ebitengine Authors
+
+package microui
+
+// Storage is a key/value store retained between frames for an ID, obtained
+// with State, for the state of custom controls like an open flag, a selection
+// or the phase of an animation.
+type Storage struct {
+	values map[string]any
+}
+
+// State returns the storage of id. It is retained in the pool of
+// WidgetState, apart from the other states of id.
+func (c *Context) State(id ID) *Storage {
+	return WidgetState[Storage](c.Widget(), id)
+}
+
+func (s *Storage) set(key string, v any) {
+	if s.values == nil {
+		s.values = map[string]any{}
+	}
+	s.values[key] = v
+}
+
+// storageValue returns the value of key in s, or def if it is not set or has
+// another type.
+func storageValue[T any](s *Storage, key string, def T) T {
+	if v, ok := s.values[key].(T); ok {
+		return v
+	}
+	return def
+}
+
+// GetInt returns the integer at key, or def if it is not set.
+func (s *Storage) GetInt(key string, def int) int {
+	return storageValue(s, key, def)
+}
+
+// SetInt sets the integer at key.
+func (s *Storage) SetInt(key string, v int) {
+	s.set(key, v)
+}
+
+// GetFloat returns the number at key, or def if it is not set.
+func (s *Storage) GetFloat(key string, def float64) float64 {
+	return storageValue(s, key, def)
+}
+
+// SetFloat sets the number at key.
+func (s *Storage) SetFloat(key string, v float64) {
+	s.set(key, v)
+}
+
+// GetBool returns the boolean at key, or def if it is not set.
+func (s *Storage) GetBool(key string, def bool) bool {
+	return storageValue(s, key, def)
+}
+
+// SetBool sets the boolean at key.
+func (s *Storage) SetBool(key string, v bool) {
+	s.set(key, v)
+}
+
+// GetString returns the string at key, or def if it is not set.
+func (s *Storage) GetString(key string, def string) string {
+	return storageValue(s, key, def)
+}
+
+// SetString sets the string at key.
+func (s *Storage) SetString(key string, v string) {
+	s.set(key, v)
+}
+
+// Delete removes the value at key.
+func (s *Storage) Delete(key string) {
+	delete(s.values, key)
+}
@@ -0,0 +1,162 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 The Ebitengine Authors
+
+package microui
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+// structField is a field of a struct edited by Struct, with the settings of
+// its mui tag.
+type structField struct {
+	index  int
+	label  string
+	min    float64
+	max    float64
+	ranged bool
+	step   float64
+	format string
+}
+
+// parseStructField parses the mui tag of f, formatted as
+// "label,min=0,max=10,step=0.1,format=%.2f". It returns false if the field
+// must be skipped.
+func parseStructField(f reflect.StructField) (structField, bool) {
+	if !f.IsExported() {
+		return structField{}, false
+	}
+	sf := structField{
+		index: f.Index[0],
+		label: f.Name,
+	}
+	tag, ok := f.Tag.Lookup("mui")
+	if tag == "-" {
+		return structField{}, false
+	}
+	if !ok {
+		return sf, true
+	}
+	items := strings.Split(tag, ",")
+	if items[0] != "" {
+		sf.label = items[0]
+	}
+	var hasMin, hasMax bool
+	for _, item := range items[1:] {
+		key, value, _ := strings.Cut(item, "=")
+		switch key {
+		case "min":
+			sf.min, _ = strconv.ParseFloat(value, 64)
+			hasMin = true
+		case "max":
+			sf.max, _ = strconv.ParseFloat(value, 64)
+			hasMax = true
+		case "step":
+			sf.step, _ = strconv.ParseFloat(value, 64)
+		case "format":
+			sf.format = value
+		}
+	}
+	sf.ranged = hasMin && hasMax
+	return sf, true
+}
+
+// Struct builds a form editing the exported fields of the struct pointed by
+// v: sliders or numbers for numeric fields, check boxes for booleans, text
+// boxes for strings, and tree nodes for nested structs. Other fields are
+// shown read-only.
+//
+// Fields are configured with mui tags like
+// `mui:"label,min=0,max=10,step=0.1,format=%.2f"`: a field with min and max
+// is edited with a slider. The tag `mui:"-"` hides a field.
+func (c *Context) Struct(v any) Response {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.Elem().Kind() != reflect.Struct {
+		panic("microui: Struct requires a pointer to a struct")
+	}
+	c.pushID(ptrToBytes(unsafe.Pointer(rv.Pointer())))
+	defer c.popID()
+	return c.structFields(rv.Elem())
+}
+
+func (c *Context) structFields(rv reflect.Value) Response {
+	var fields []structField
+	labelw := 0
+	for i := 0; i < rv.NumField(); i++ {
+		sf, ok := parseStructField(rv.Type().Field(i))
+		if !ok {
+			continue
+		}
+		fields = append(fields, sf)
+		labelw = max(labelw, textWidth(sf.label))
+	}
+
+	var res Response
+	for _, sf := range fields {
+		fv := rv.Field(sf.index)
+		if fv.Kind() == reflect.Struct {
+			c.TreeNode(sf.label, func(Response) {
+				res |= c.structFields(fv)
+			})
+			continue
+		}
+		c.SetLayoutRow([]int{labelw + c.Style.Padding*2, -1}, 0)
+		c.Label(sf.label)
+		res |= c.structField(fv, sf)
+	}
+	return res
+}
+
+func (c *Context) structField(fv reflect.Value, sf structField) Response {
+	name := "!field" + sf.label
+	switch fv.Kind() {
+	case reflect.Bool:
+		return c.CheckboxBinding(name, Binding[bool]{
+			Get: fv.Bool,
+			Set: fv.SetBool,
+		})
+	case reflect.String:
+		return c.TextBoxBinding(name, Binding[string]{
+			Get: fv.String,
+			Set: fv.SetString,
+		}, 0)
+	case reflect.Float32, reflect.Float64:
+		return c.structNumber(name, sf, sliderFmt, 0, Binding[float64]{
+			Get: fv.Float,
+			Set: fv.SetFloat,
+		})
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return c.structNumber(name, sf, "%.0f", 1, Binding[float64]{
+			Get: func() float64 { return float64(fv.Int()) },
+			Set: func(value float64) { fv.SetInt(int64(math.Round(value))) },
+		})
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return c.structNumber(name, sf, "%.0f", 1, Binding[float64]{
+			Get: func() float64 { return float64(fv.Uint()) },
+			Set: func(value float64) { fv.SetUint(uint64(math.Round(math.Max(value, 0)))) },
+		})
+	}
+	c.Label(fmt.Sprint(fv.Interface()))
+	return 0
+}
+
+func (c *Context) structNumber(name string, sf structField, format string, step float64, b Binding[float64]) Response {
+	if sf.format != "" {
+		format = sf.format
+	}
+	if sf.step != 0 {
+		step = sf.step
+	}
+	if sf.ranged {
+		return c.SliderBinding(name, b, sf.min, sf.max, step, format, OptAlignCenter)
+	}
+	if step == 0 {
+		step = 0.1
+	}
+	return c.NumberBinding(name, b, step, format, OptAlignCenter)
+}
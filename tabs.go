@@ -0,0 +1,154 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 The Ebitengine Authors
+
+package microui
+
+import (
+	"image"
+	"strconv"
+)
+
+// TabBarEvent reports the interactions with a tab bar in a frame.
+type TabBarEvent struct {
+	// Close is the index of the tab to close, or -1.
+	Close int
+
+	// MoveFrom and MoveTo are the old and new indices of a dragged tab, or -1.
+	// The selected index already follows the move.
+	MoveFrom int
+	MoveTo   int
+}
+
+// tabID returns the ID of the tab at index i with label, so that tabs with the
+// same label have their own IDs.
+func (c *Context) tabID(label string, i int) ID {
+	return c.id([]byte(strconv.Itoa(i) + "!tab" + label))
+}
+
+func (c *Context) tabWidth(label string, h int) int {
+	return textWidth(label) + c.Style.Padding*2 + h
+}
+
+// TabBar builds a bar of tabs with the given labels in the next layout cell,
+// and selects a tab by setting selected to its index.
+// A tab can be closed with its close button or a middle click, and can be
+// reordered by dragging. The tabs not fitting in the bar are listed in a
+// dropdown menu at its right. The labels don't have to be unique.
+func (c *Context) TabBar(labels []string, selected *int) TabBarEvent {
+	ev := TabBarEvent{
+		Close:    -1,
+		MoveFrom: -1,
+		MoveTo:   -1,
+	}
+	c.Control(0, 0, func(r image.Rectangle) Response {
+		h := r.Dy()
+		menu := image.Rect(r.Max.X-h, r.Min.Y, r.Max.X, r.Max.Y)
+
+		// find the range of tabs to show, including the selected one
+		avail := r.Dx()
+		var total int
+		for _, l := range labels {
+			total += c.tabWidth(l, h) + c.Style.Spacing
+		}
+		overflow := total > avail
+		if overflow {
+			avail -= h + c.Style.Spacing
+		}
+		var start int
+		for start < *selected && *selected < len(labels) {
+			var w int
+			for _, l := range labels[start : *selected+1] {
+				w += c.tabWidth(l, h) + c.Style.Spacing
+			}
+			if w <= avail {
+				break
+			}
+			start++
+		}
+
+		x := r.Min.X
+		for i := start; i < len(labels); i++ {
+			label := labels[i]
+			w := c.tabWidth(label, h)
+			if x+w > r.Min.X+avail {
+				break
+			}
+			tab := image.Rect(x, r.Min.Y, x+w, r.Max.Y)
+			x += w + c.Style.Spacing
+
+			id := c.tabID(label, i)
+			c.addTabStop(id)
+			c.updateControl(id, tab, 0)
+			closeRect := image.Rect(tab.Max.X-h, tab.Min.Y, tab.Max.X, tab.Max.Y)
+			closeID := fnv1a(id, []byte("!close"))
+			c.updateControl(closeID, closeRect, 0)
+
+			// handle input
+			if c.activated(id) {
+				*selected = i
+			}
+			if c.mousePressed == mouseMiddle && c.focus == id {
+				ev.Close = i
+			}
+			if c.mousePressed == mouseLeft && c.focus == closeID {
+				ev.Close = i
+			}
+			if c.focus == id && c.mouseDown == mouseLeft {
+				to := i
+				if c.mousePos.X < tab.Min.X && i > 0 {
+					to = i - 1
+				} else if c.mousePos.X >= tab.Max.X && i < len(labels)-1 {
+					to = i + 1
+				}
+				if to != i && ev.MoveFrom < 0 {
+					ev.MoveFrom, ev.MoveTo = i, to
+					if *selected == i {
+						*selected = to
+					}
+					// the dragged tab keeps the focus at its new index
+					c.focus = c.tabID(label, to)
+				}
+			}
+
+			// draw
+			colorid := ColorButton
+			if i == *selected {
+				colorid = ColorButtonFocus
+			} else if c.hover == id || c.hover == closeID {
+				colorid = ColorButtonHover
+			}
+			c.drawFrame(tab, colorid)
+			c.drawControlText(label, image.Rect(tab.Min.X, tab.Min.Y, closeRect.Min.X, tab.Max.Y), ColorText, 0)
+			c.drawIcon(iconClose, closeRect, c.Style.Colors[ColorText])
+		}
+
+		// overflow menu
+		if overflow {
+			id := c.id([]byte("!tabmenu"))
+			c.addTabStop(id)
+			c.updateControl(id, menu, 0)
+			if c.activated(id) {
+				c.OpenPopup("!tabs")
+			}
+			c.drawControlFrame(id, menu, ColorButton, 0)
+			c.drawIcon(iconExpanded, menu, c.Style.Colors[ColorText])
+		}
+		return 0
+	})
+	c.Popup("!tabs", func(res Response) {
+		var w int
+		for _, l := range labels {
+			w = max(w, textWidth(l))
+		}
+		c.SetLayoutRow([]int{w + c.Style.Padding*2}, 0)
+		for i, l := range labels {
+			c.pushID([]byte(strconv.Itoa(i)))
+			if c.Button(l) != 0 {
+				*selected = i
+				c.CurrentContainer().Open = false
+			}
+			c.popID()
+		}
+	})
+	return ev
+}
@@ -0,0 +1,118 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 The Ebitengine Authors
+
+package microui
+
+import (
+	"image"
+	"slices"
+	"strings"
+	"unsafe"
+)
+
+// TagInput edits a list of tags, shown as chips with a button removing them,
+// followed by a text entry adding a tag when Enter or a comma is typed. The
+// chips wrap to new rows when they don't fit in the width of the layout.
+// Backspace in the empty entry removes the last tag. Empty and duplicate tags
+// aren't added. It reports ResponseChange when the tags change.
+func (c *Context) TagInput(tags *[]string) Response {
+	id := c.id(ptrToBytes(unsafe.Pointer(tags)))
+	c.pushID(ptrToBytes(unsafe.Pointer(tags)))
+	defer c.popID()
+
+	var res Response
+	old := slices.Clone(*tags)
+	c.LayoutColumn(func() {
+		h := c.ControlHeight()
+		avail := c.layout().body.Dx()
+
+		// lay out the chips in rows, with the entry at the end of the last one
+		var rows [][]int
+		var row []int
+		var x int
+		for _, tag := range *tags {
+			w := textWidth(tag) + c.Style.Padding*2 + h
+			if x > 0 && x+w > avail {
+				rows, row, x = append(rows, row), nil, 0
+			}
+			row = append(row, w)
+			x += w + c.Style.Spacing
+		}
+		if x > 0 && x+c.Style.Size.X > avail {
+			rows, row = append(rows, row), nil
+		}
+		rows = append(rows, append(row, -1))
+
+		remove := -1
+		var i int
+		for _, widths := range rows {
+			c.SetLayoutRow(widths, h)
+			for range widths {
+				if i == len(*tags) {
+					break
+				}
+				if c.tagChip((*tags)[i]) {
+					remove = i
+				}
+				i++
+			}
+		}
+
+		// the entry is the last cell of the last row
+		entryID := c.id([]byte("!entry"))
+		text := WidgetState[string](c.Widget(), fnv1a(entryID, []byte("!text")))
+		entry := *text
+		entryRes := c.textBoxRaw(&entry, entryID, textBoxConfig{}, 0)
+		if c.focus == entryID && entry == "" && (c.keyPressed&keyBackspace) != 0 && len(*tags) > 0 && (entryRes&ResponseChange) == 0 {
+			remove = len(*tags) - 1
+		}
+		if parts := strings.Split(entry, ","); len(parts) > 1 || (entryRes&ResponseSubmit) != 0 {
+			if (entryRes & ResponseSubmit) != 0 {
+				entry = ""
+				// keep typing tags after Enter
+				c.SetFocus(entryID)
+			} else {
+				entry, parts = parts[len(parts)-1], parts[:len(parts)-1]
+				c.textCaret, c.textAnchor = len(entry), len(entry)
+			}
+			for _, p := range parts {
+				if p = strings.TrimSpace(p); p != "" && !slices.Contains(*tags, p) {
+					*tags = append(*tags, p)
+				}
+			}
+		}
+		*text = entry
+
+		if remove >= 0 {
+			*tags = slices.Delete(*tags, remove, remove+1)
+		}
+	})
+	if !slices.Equal(old, *tags) {
+		c.eventValues(old, slices.Clone(*tags))
+		res |= ResponseChange
+	}
+	c.emitEvents(id, res)
+	return res
+}
+
+// tagChip draws a chip of a TagInput showing tag in the next cell of the
+// layout, and reports whether its remove button was clicked.
+func (c *Context) tagChip(tag string) bool {
+	var removed bool
+	c.Control(0, 0, func(r image.Rectangle) Response {
+		closeRect := image.Rect(r.Max.X-r.Dy(), r.Min.Y, r.Max.X, r.Max.Y)
+		closeID := c.id([]byte("!remove" + tag))
+		c.addTabStop(closeID)
+		c.updateControl(closeID, closeRect, 0)
+		removed = c.activated(closeID)
+
+		c.drawFrame(r, ColorButton)
+		if c.hover == closeID {
+			c.drawFrame(closeRect, ColorButtonHover)
+		}
+		c.drawControlText(tag, image.Rect(r.Min.X, r.Min.Y, closeRect.Min.X, r.Max.Y), ColorText, 0)
+		c.drawIcon(iconClose, closeRect, c.Style.Colors[ColorText])
+		return 0
+	})
+	return removed
+}
@@ -0,0 +1,146 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 The Ebitengine Authors
+
+package microui
+
+import (
+	"image"
+	"math"
+	"strconv"
+	"unsafe"
+)
+
+// TimelineState is the state of a timeline built with Timeline.
+type TimelineState struct {
+	// Time is the time at the playhead, between 0 and Duration.
+	Time     float64
+	Duration float64
+
+	// the shown range of time, or the whole duration if empty
+	viewStart, viewEnd float64
+}
+
+// TimelineTrack is a row of a timeline.
+type TimelineTrack struct {
+	Name string
+	// Height is the height of the row, or 0 for the height of a control.
+	Height int
+	// Draw draws the clips of the track in r, if it is not nil. x returns the
+	// position in r of a time.
+	Draw func(w WidgetContext, r image.Rectangle, x func(t float64) int)
+}
+
+// view returns the shown range of time.
+func (s *TimelineState) view() (float64, float64) {
+	if s.viewEnd <= s.viewStart {
+		return 0, math.Max(s.Duration, 1)
+	}
+	return s.viewStart, s.viewEnd
+}
+
+// Timeline is a timeline with a time axis, where the playhead is moved by
+// clicking or dragging, and a row per track. The mouse wheel zooms the time
+// axis, and dragging with the middle mouse button pans it.
+func (c *Context) Timeline(s *TimelineState, tracks []TimelineTrack) Response {
+	c.pushID(ptrToBytes(unsafe.Pointer(s)))
+	defer c.popID()
+
+	var res Response
+	c.LayoutColumn(func() {
+		labelw := 0
+		for _, t := range tracks {
+			labelw = max(labelw, textWidth(t.Name))
+		}
+		labelw += c.Style.Padding * 2
+
+		c.SetLayoutRow([]int{labelw, -1}, 0)
+		c.Label("")
+		res = c.timelineAxis(s)
+		for i, t := range tracks {
+			c.SetLayoutRow([]int{labelw, -1}, t.Height)
+			c.Label(t.Name)
+			id := c.id([]byte("!track" + strconv.Itoa(i)))
+			c.Control(id, OptHoldFocus, func(r image.Rectangle) Response {
+				c.timelineView(s, id, r)
+				c.drawControlFrame(id, r, ColorBase, 0)
+				c.pushClipRect(r)
+				defer c.popClipRect()
+				if t.Draw != nil {
+					t.Draw(c.Widget(), r, func(time float64) int {
+						return timelineX(s, r, time)
+					})
+				}
+				c.drawPlayhead(s, r)
+				return 0
+			})
+		}
+	})
+	return res
+}
+
+// timelineX returns the position in r of the time t.
+func timelineX(s *TimelineState, r image.Rectangle, t float64) int {
+	start, end := s.view()
+	return r.Min.X + int(math.Round((t-start)/(end-start)*float64(r.Dx())))
+}
+
+// timelineTime returns the time at the position x in r.
+func timelineTime(s *TimelineState, r image.Rectangle, x int) float64 {
+	start, end := s.view()
+	return start + float64(x-r.Min.X)/float64(r.Dx())*(end-start)
+}
+
+// timelineView zooms and pans the time axis shown in the area r of the
+// control id.
+func (c *Context) timelineView(s *TimelineState, id ID, r image.Rectangle) {
+	start, end := s.view()
+	if c.mouseOver(r) && c.scrollDelta.Y != 0 {
+		t := timelineTime(s, r, c.mousePos.X)
+		f := math.Pow(1.1, float64(c.scrollDelta.Y)/30)
+		start, end = t+(start-t)*f, t+(end-t)*f
+		// consume the wheel so that it doesn't scroll the container
+		c.scrollDelta = image.Point{}
+	}
+	if c.focus == id && (c.mouseDown&mouseMiddle) != 0 {
+		d := float64(c.mouseDelta.X) / float64(r.Dx()) * (end - start)
+		start, end = start-d, end-d
+	}
+	s.viewStart, s.viewEnd = start, end
+}
+
+// timelineAxis is the time axis of a timeline, with a tick mark and a label
+// at every power of ten.
+func (c *Context) timelineAxis(s *TimelineState) Response {
+	id := c.id([]byte("!axis"))
+	return c.Control(id, OptHoldFocus, func(r image.Rectangle) Response {
+		var res Response
+		c.timelineView(s, id, r)
+		if c.focus == id && c.mouseDown == mouseLeft {
+			if t := clampF(timelineTime(s, r, c.mousePos.X), 0, s.Duration); t != s.Time {
+				s.Time = t
+				res |= ResponseChange
+			}
+		}
+
+		c.drawControlFrame(id, r, ColorBase, 0)
+		c.pushClipRect(r)
+		defer c.popClipRect()
+		start, end := s.view()
+		step := math.Pow(10, math.Floor(math.Log10((end-start)/5)))
+		clr := c.Style.Colors[ColorText]
+		for k := math.Ceil(start / step); k*step <= end; k++ {
+			t := k * step
+			x := timelineX(s, r, t)
+			c.drawRect(image.Rect(x, r.Max.Y-4, x+1, r.Max.Y), clr)
+			c.drawText(c.Localizer.Number("%g", t), image.Pt(x+2, r.Min.Y+(r.Dy()-lineHeight())/2), clr)
+		}
+		c.drawPlayhead(s, r)
+		return res
+	})
+}
+
+// drawPlayhead draws the playhead of s across r.
+func (c *Context) drawPlayhead(s *TimelineState, r image.Rectangle) {
+	x := timelineX(s, r, s.Time)
+	c.drawRect(image.Rect(x, r.Min.Y, x+1, r.Max.Y), c.Style.Colors[ColorHighlight])
+}
@@ -0,0 +1,101 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 The Ebitengine Authors
+
+package microui
+
+import "image"
+
+// tooltip shows a tooltip built with f if the last control has been hovered
+// for the tooltip delay.
+func (c *Context) tooltip(f func()) {
+	c.tooltipAt(c.lastRect, f)
+}
+
+// tooltipAt shows a tooltip built with f if rect has been hovered for the
+// tooltip delay.
+func (c *Context) tooltipAt(rect image.Rectangle, f func()) {
+	if !c.mouseOver(rect) {
+		return
+	}
+	c.tooltipHovered = true
+	if c.tooltipRect != rect {
+		c.tooltipRect = rect
+		c.tooltipStart = c.now
+	}
+	if c.now-c.tooltipStart >= c.Style.TooltipDelay {
+		c.tooltipFunc = f
+		c.tooltipRoot = c.rootContainer()
+	}
+}
+
+// Tooltip shows text in a tooltip while the last control is hovered.
+func (c *Context) Tooltip(text string) {
+	c.tooltip(func() {
+		c.SetLayoutRow([]int{textWidth(text) + c.Style.Padding*2}, 0)
+		c.Label(text)
+	})
+}
+
+// TooltipPanel shows a tooltip built with f while the last control is
+// hovered, for previews with any controls, like images drawn with DrawControl
+// or a grid of labels.
+func (c *Context) TooltipPanel(f func()) {
+	c.tooltip(f)
+}
+
+// helpMarkerWidth is the largest width of the text of a help marker tooltip,
+// which is wrapped beyond it.
+const helpMarkerWidth = 240
+
+// HelpMarker is a small "(?)" marker in the next cell of the layout, usually
+// right after the control it documents, showing text wrapped in a tooltip
+// while it is hovered.
+func (c *Context) HelpMarker(text string) {
+	c.LabelEx("(?)", OptAlignCenter)
+	c.tooltip(func() {
+		c.SetLayoutRow([]int{min(textWidth(text), helpMarkerWidth) + c.Style.Padding*2}, 0)
+		c.Text(text)
+	})
+}
+
+// MarkInvalid marks the last control as invalid: it is framed with the error
+// color, and msg is shown in a tooltip while it is hovered.
+func (c *Context) MarkInvalid(msg string) {
+	c.drawInvalid(c.lastRect)
+	if msg != "" {
+		c.Tooltip(msg)
+	}
+}
+
+func (c *Context) tooltipWindow() {
+	if !c.tooltipHovered {
+		c.tooltipRect = image.Rectangle{}
+	}
+	c.tooltipHovered = false
+	if c.tooltipFunc == nil {
+		return
+	}
+	f := c.tooltipFunc
+	c.tooltipFunc = nil
+
+	// position below the cursor, on top of everything else, where the window
+	// of the hovered control is drawn
+	cnt := c.Container("!tooltip")
+	inheritTarget(cnt, c.tooltipRoot)
+	c.tooltipRoot = nil
+	pos := c.targetCursor(cnt).Add(image.Pt(0, 20))
+	size := cnt.Rect.Size()
+	if size.X == 0 || size.Y == 0 {
+		size = image.Pt(1, 1)
+	}
+	cnt.Rect = image.Rectangle{Min: pos, Max: pos.Add(size)}
+	cnt.Open = true
+	cnt.Layer = LayerOverlay
+	if cnt.ZIndex < c.lastZIndex {
+		c.bringToFront(cnt)
+	}
+	opt := OptAutoSize | OptNoResize | OptNoScroll | OptNoTitle | OptNoInteract
+	c.window("!tooltip", cnt.Rect, opt, func(res Response) {
+		f()
+	})
+}
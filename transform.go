@@ -0,0 +1,102 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 The Ebitengine Authors
+
+package microui
+
+import (
+	"image"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// transform maps the UI space to the screen space, scaling then translating.
+// The zero transform is the identity.
+type transform struct {
+	scale float64
+	x, y  float64
+}
+
+func (t transform) scaleOrOne() float64 {
+	if t.scale == 0 {
+		return 1
+	}
+	return t.scale
+}
+
+// apply returns the screen position of the UI position (x, y).
+func (t transform) apply(x, y float64) (float64, float64) {
+	s := t.scaleOrOne()
+	return x*s + t.x, y*s + t.y
+}
+
+// invert returns the UI position of the screen position (x, y).
+func (t transform) invert(x, y int) (int, int) {
+	s := t.scaleOrOne()
+	return int(math.Floor((float64(x) - t.x) / s)), int(math.Floor((float64(y) - t.y) / s))
+}
+
+// invertRect returns the UI area covering the screen area r.
+func (t transform) invertRect(r image.Rectangle) image.Rectangle {
+	x0, y0 := t.invert(r.Min.X, r.Min.Y)
+	x1, y1 := t.invert(r.Max.X, r.Max.Y)
+	return image.Rect(x0, y0, x1+1, y1+1)
+}
+
+// rect returns the screen area covered by the UI area r.
+func (t transform) rect(r image.Rectangle) image.Rectangle {
+	x0, y0 := t.apply(float64(r.Min.X), float64(r.Min.Y))
+	x1, y1 := t.apply(float64(r.Max.X), float64(r.Max.Y))
+	return image.Rect(int(math.Floor(x0)), int(math.Floor(y0)), int(math.Ceil(x1)), int(math.Ceil(y1)))
+}
+
+// then returns the transform applying t then u.
+func (t transform) then(u transform) transform {
+	x, y := u.apply(t.x, t.y)
+	return transform{scale: t.scaleOrOne() * u.scaleOrOne(), x: x, y: y}
+}
+
+// localTransform returns the transform of the scaled space of the window cnt
+// to the UI space.
+func localTransform(cnt *Container) transform {
+	if cnt.Scale == 0 || cnt.Scale == 1 {
+		return transform{}
+	}
+	return transform{
+		scale: cnt.Scale,
+		x:     float64(cnt.Rect.Min.X) * (1 - cnt.Scale),
+		y:     float64(cnt.Rect.Min.Y) * (1 - cnt.Scale),
+	}
+}
+
+// pushTransform sets the transform of the following commands, applied before
+// the transform of the context.
+func (c *Context) pushTransform(t transform) {
+	cmd := c.pushCommand(commandTransform)
+	cmd.transform.transform = t
+	c.cmdTransform = t
+}
+
+// geoM returns the matrix of the transform, applied after m.
+func (t transform) geoM(m ebiten.GeoM) ebiten.GeoM {
+	s := t.scaleOrOne()
+	m.Scale(s, s)
+	m.Translate(t.x, t.y)
+	return m
+}
+
+// SetTransform sets the transform of the whole UI to the screen: the UI is
+// scaled by scale, then moved by (x, y). The geometry drawn by Draw is
+// transformed, and the pointer coordinates are transformed back to the UI
+// space, so that the UI can be zoomed and panned in an editor viewport, or
+// letterboxed at a fixed virtual resolution.
+//
+// The custom draw functions of DrawControl draw to the screen without the
+// transform.
+func (c *Context) SetTransform(scale, x, y float64) {
+	t := transform{scale: scale, x: x, y: y}
+	if t != c.transform {
+		c.redraw = true
+	}
+	c.transform = t
+}
@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 The Ebitengine Authors
+
+package microui
+
+import "image"
+
+// Transition is the animation of the windows and the popups when they are
+// opened or closed, set with Style.WindowTransition.
+type Transition int
+
+const (
+	// TransitionNone shows and hides the windows at once.
+	TransitionNone Transition = iota
+	// TransitionFade fades the windows in and out.
+	TransitionFade
+	// TransitionScale fades the windows and scales them from their center,
+	// or from the anchor of a popup.
+	TransitionScale
+	// TransitionSlide fades the windows and slides them up when they open,
+	// and down when they close.
+	TransitionSlide
+)
+
+// transitionScale is the scale of a window at the start of TransitionScale.
+const transitionScale = 0.8
+
+// windowTransition updates the transition of the window cnt, and returns its
+// progress, from 0 for a hidden window to 1 for a window fully shown, and
+// whether the window is closing and still shown. A transition reversed
+// midway goes back from where it is.
+func (c *Context) windowTransition(cnt *Container, opt Option) (float64, bool) {
+	open := cnt.Open && !cnt.Minimized
+	if cnt.builtAt != c.tick-1 {
+		// the window wasn't built in the last frame
+		cnt.shown = false
+		cnt.transitionAt = c.now - c.Style.TransitionDuration
+	}
+	cnt.builtAt = c.tick
+
+	d := c.Style.TransitionDuration
+	if c.Style.WindowTransition == TransitionNone || d <= 0 || (opt&OptNoTransition) != 0 || cnt.Minimized {
+		cnt.shown = open
+		return 1, false
+	}
+	if open != cnt.shown {
+		cnt.shown = open
+		cnt.transitionAt = c.now - max64(d-(c.now-cnt.transitionAt), 0)
+	}
+	t := minF(float64(c.now-cnt.transitionAt)/float64(d), 1)
+	if open {
+		return t, false
+	}
+	return 1 - t, t < 1
+}
+
+// transitionTransform returns the transform of the window cnt in the UI space
+// at the progress t of its transition, with local the transform of its
+// scaled space to the UI space.
+func (c *Context) transitionTransform(cnt *Container, local transform, t float64) transform {
+	r := local.rect(cnt.Rect)
+	switch c.Style.WindowTransition {
+	case TransitionScale:
+		center := r.Min.Add(r.Size().Div(2))
+		if cnt.popup {
+			a := cnt.popupAnchor
+			center = image.Pt(a.Min.X+a.Dx()/2, a.Max.Y)
+		}
+		s := transitionScale + (1-transitionScale)*t
+		return transform{
+			scale: s,
+			x:     float64(center.X) * (1 - s),
+			y:     float64(center.Y) * (1 - s),
+		}
+	case TransitionSlide:
+		return transform{y: float64(r.Dy()) / 4 * (1 - t)}
+	}
+	return transform{}
+}
+
+// applyTransition draws the window cnt at the progress t of its transition,
+// faded and transformed, and returns a function restoring the fade.
+func (c *Context) applyTransition(cnt *Container, local transform, t float64) func() {
+	fade := c.fade
+	c.fade = maxF(fade, 1-t)
+	if t < 1 {
+		c.pushTransform(local.then(c.transitionTransform(cnt, local, t)))
+	}
+	return func() {
+		c.fade = fade
+	}
+}
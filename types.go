@@ -6,6 +6,7 @@ package microui
 import (
 	"image"
 	"image/color"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
 )
@@ -31,19 +32,29 @@ type clipCommand struct {
 
 type rectCommand struct {
 	rect  image.Rectangle
-	color color.Color
+	color color.RGBA64
 }
 
 type textCommand struct {
 	pos   image.Point
-	color color.Color
+	color color.RGBA64
 	str   string
 }
 
 type iconCommand struct {
 	rect  image.Rectangle
 	icon  icon
-	color color.Color
+	color color.RGBA64
+}
+
+type transformCommand struct {
+	transform transform
+}
+
+type polylineCommand struct {
+	points []image.Point
+	color  color.RGBA64
+	width  float64
 }
 
 type drawCommand struct {
@@ -61,6 +72,50 @@ type layout struct {
 	indent    int
 }
 
+// Token is a run of bytes of a line in a code editor, drawn with Color.
+type Token struct {
+	Len   int
+	Color color.Color
+}
+
+type TreeItem struct {
+	Label    string
+	Children []*TreeItem
+}
+
+type textBoxConfig struct {
+	maxLen int
+	filter func(r rune) bool
+}
+
+type dragState struct {
+	root    *Container
+	rect    image.Rectangle
+	start   image.Point
+	active  bool
+	typ     string
+	payload any
+	preview func()
+}
+
+type tabStop struct {
+	id   ID
+	root *Container
+}
+
+type treeCheck struct {
+	force     *TriState
+	checked   int
+	unchecked int
+	mixed     int
+}
+
+type treeNodeRequest struct {
+	expanded  bool
+	recursive bool
+	pending   bool
+}
+
 type command struct {
 	typ  int
 	idx  int
@@ -71,6 +126,9 @@ type command struct {
 	text textCommand // type 4
 	icon iconCommand // type 5
 	draw drawCommand // type 6
+
+	transform transformCommand // type 7
+	polyline  polylineCommand  // type 8
 }
 
 type Container struct {
@@ -82,6 +140,97 @@ type Container struct {
 	Scroll      image.Point
 	ZIndex      int
 	Open        bool
+
+	// Layer is the layer of the window. The windows are sorted by z-index
+	// within their layer.
+	Layer Layer
+
+	// Scale is the scale of the layout and the drawing of the window, around
+	// the top-left corner of Rect. Rect is in the scaled space of the window,
+	// so the window covers Rect.Min + Rect.Size()*Scale on the screen. Zero
+	// means 1.
+	Scale float64
+
+	// Offscreen reports whether the window is drawn to an image with
+	// DrawWindow instead of Draw, for example on an in-world surface. Rect is
+	// then in the space of the image, and so is the cursor set with
+	// SetWindowCursor.
+	Offscreen bool
+
+	// Viewport is the viewport set with SetViewport showing the window, or 0
+	// for the screen drawn by Draw.
+	Viewport int
+
+	// Minimized reports whether the window is hidden and shown as a button in
+	// the taskbar instead.
+	Minimized bool
+
+	// Style overrides the style of the context for the window or the panel,
+	// if it is not nil, like a HUD window without padding.
+	Style *ContainerStyle
+
+	// DragRegion is the area by which the window is dragged, in addition to
+	// its title bar, and DragRect the area of DragRegionCustom, relative to
+	// the top-left corner of the window. The window can't be dragged with
+	// OptNoMove.
+	DragRegion DragRegion
+	DragRect   image.Rectangle
+
+	// CloseHandler is called when the close button of the window is pressed,
+	// if it is not nil. The window is closed only if it returns true, so that
+	// closing can be confirmed first, for example in a popup.
+	CloseHandler func() bool
+
+	// name is the title of the window using this container, if any, and id
+	// its ID.
+	name        string
+	id          ID
+	minimizedAt int
+	anchor      ID
+	anchorY     int
+
+	// popup reports whether the window is an auto-sized popup placed next to
+	// popupAnchor, and measuring whether its content is being measured.
+	popup       bool
+	popupAnchor image.Rectangle
+	measuring   bool
+
+	// titleRect is the title bar of the window on the screen, if it has one.
+	// tabHost is the host of the group of tabbed windows of the window, if
+	// it isn't the host, and tabs the other windows of the group of a host,
+	// with activeTab the window of the selected tab, or nil for the host.
+	titleRect image.Rectangle
+	tabHost   *Container
+	tabs      []*Container
+	activeTab *Container
+
+	// rooted marks the root containers while they are sorted.
+	rooted bool
+
+	// shown reports whether the window was shown in the last frame it was
+	// built, the frame builtAt, and transitionAt is the start of its last
+	// opening or closing transition.
+	shown        bool
+	builtAt      int
+	transitionAt time.Duration
+
+	// cursor is the position of the cursor of an offscreen window.
+	cursor     image.Point
+	lastCursor image.Point
+	cursorIn   bool
+
+	// owner is the offscreen window whose image shows the popup, the
+	// tooltip or the drag preview opened from it.
+	owner *Container
+}
+
+// WindowInfo describes a window.
+type WindowInfo struct {
+	Name   string
+	Rect   image.Rectangle
+	Open   bool
+	ZIndex int
+	Layer  Layer
 }
 
 type Style struct {
@@ -92,50 +241,251 @@ type Style struct {
 	TitleHeight   int
 	ScrollbarSize int
 	ThumbSize     int
-	Colors        [ColorMax + 1]color.RGBA
+
+	// TooltipDelay is how long a control has to be hovered to show its
+	// tooltip.
+	TooltipDelay time.Duration
+	// KeyRepeatDelay is how long a key editing text or moving the caret or
+	// the focus has to be held to be repeated, and KeyRepeatInterval the
+	// interval between the repeats.
+	KeyRepeatDelay    time.Duration
+	KeyRepeatInterval time.Duration
+	// DoubleClickTime is the maximum interval between the two clicks of a
+	// double click.
+	DoubleClickTime time.Duration
+	// CaretBlinkInterval is the interval between the blinks of the caret of
+	// the text being edited, or 0 for a caret which doesn't blink. The caret
+	// doesn't blink while typing.
+	CaretBlinkInterval time.Duration
+	// WindowTransition is the animation of the windows and the popups when
+	// they are opened or closed, lasting TransitionDuration. It is disabled
+	// for a window with OptNoTransition.
+	WindowTransition   Transition
+	TransitionDuration time.Duration
+
+	// FocusRingWidth is the thickness of the ring drawn around the control
+	// focused with the keyboard.
+	FocusRingWidth int
+
+	// RightToLeft makes right-to-left the direction of the text and aligns it
+	// to the right by default, for the languages written from right to left.
+	RightToLeft bool
+
+	// WheelScroll is the distance scrolled by a notch of the mouse wheel, in
+	// pixels, or in lines of text if WheelScrollLines is true.
+	WheelScroll      float64
+	WheelScrollLines bool
+
+	// FineDragFactor and CoarseDragFactor scale the steps of number and
+	// slider drags while Shift, or Control or Alt, is held.
+	FineDragFactor   float64
+	CoarseDragFactor float64
+
+	Colors [ColorMax + 1]color.RGBA
+}
+
+// ContainerStyle is the style of a window or a panel which can be overridden
+// per container with Container.Style.
+type ContainerStyle struct {
+	// Padding is the space around the content.
+	Padding       int
+	TitleHeight   int
+	ScrollbarSize int
+}
+
+// ContainerStyle returns the container style given by s, to be modified and
+// set to a container.
+func (s *Style) ContainerStyle() ContainerStyle {
+	return ContainerStyle{
+		Padding:       s.Padding,
+		TitleHeight:   s.TitleHeight,
+		ScrollbarSize: s.ScrollbarSize,
+	}
+}
+
+// caretState is the state of the caret of a control, at pos in a text of
+// length n.
+type caretState struct {
+	id     ID
+	pos, n int
+}
+
+// Clipboard is the interface to the clipboard used by a context, for copying,
+// cutting and pasting in the text boxes with Control+C, Control+X and
+// Control+V. It can be implemented with the clipboard of the OS, or of the
+// browser with WebAssembly.
+type Clipboard interface {
+	Text() string
+	SetText(text string)
+}
+
+type memoryClipboard struct {
+	text string
+}
+
+func (m *memoryClipboard) Text() string {
+	return m.text
+}
+
+func (m *memoryClipboard) SetText(text string) {
+	m.text = text
 }
 
 type Context struct {
 	// core state
 
-	Style         *Style
-	hover         ID
-	focus         ID
-	LastID        ID
-	lastRect      image.Rectangle
-	lastZIndex    int
-	keepFocus     bool
-	tick          int
-	hoverRoot     *Container
-	nextHoverRoot *Container
-	scrollTarget  *Container
-	numberEditBuf string
-	numberEdit    ID
+	Style *Style
+	// Clipboard is the clipboard for copying and pasting text. By default, it
+	// only holds the text within the application.
+	Clipboard Clipboard
+	// KeyboardNavigation enables operating all the controls with the keyboard:
+	// Enter or Space activates the focused control, the focused control is
+	// scrolled into view, Control+Tab switches windows, and Up and Down move
+	// the focus between the items of a popup, which opens below the focused
+	// control. PageUp, PageDown, Home and End scroll the container of the
+	// focused control, or else the one under the mouse, in any mode, unless
+	// the focused control uses these keys.
+	KeyboardNavigation bool
+	// ReadOnly shows the UI without allowing any interaction with the
+	// controls, which are drawn faded, like for a spectator or a replay. The
+	// containers can still be scrolled with the mouse wheel.
+	ReadOnly bool
+	// Localizer translates the strings shown by the package and formats the
+	// numbers. By default, the strings are in English.
+	Localizer Localizer
+	// EventSink, if not nil, receives an event for every submit and change of
+	// a control with an ID, so that the interactions can be recorded or
+	// scripted without wrapping the controls.
+	EventSink func(e Event)
+
+	hover          ID
+	focus          ID
+	LastID         ID
+	lastRect       image.Rectangle
+	lastError      error
+	lastZIndex     int
+	keepFocus      bool
+	tick           int
+	now            time.Duration
+	deltaTime      time.Duration
+	hoverRoot      *Container
+	nextHoverRoot  *Container
+	scrollTarget   *Container
+	panTarget      *Container
+	focusByKey     bool
+	focusMoved     bool
+	focusRing      image.Rectangle
+	ringPending    bool
+	controlDepth   int
+	focusContainer *Container
+	modal          *Container
+	nextModal      *Container
+	numberEditBuf  string
+	numberEdit     ID
+	dragRatio      float64
+	scrollGrab     int
+	scrollGrabbed  bool
+	textHighlight  string
+	textEdit       ID
+	textEditOrig   string
+	textCaret      int
+	textAnchor     int
+	textCaretRect  image.Rectangle
+	caretState     caretState
+	caretBlinkAt   time.Duration
+	composition    string
+	suggest        ID
+	suggestIndex   int
+	suggestHidden  bool
+	keyboardTarget ID
+	keyboardShift  bool
+	virtualInput   []rune
+	virtualKeys    int
+
+	tooltipRect    image.Rectangle
+	tooltipStart   time.Duration
+	tooltipHovered bool
+	tooltipFunc    func()
+	tooltipRoot    *Container
+
+	drag *dragState
+	fade float64
+
+	titleDrag      *Container
+	titleDragMoved bool
+
+	annotations  []annotation
+	stateUpdates []containerUpdate
+	budget       *frameBudget
+
+	transform    transform
+	viewports    map[int]Viewport
+	cmdTransform transform
+
+	dirty        bool
+	redraw       bool
+	lastCommands []command
+
+	eventOld   any
+	eventNew   any
+	muteEvents int
+
+	screenCursor     image.Point
+	lastScreenCursor image.Point
+	screenRect       image.Rectangle
+
+	profiling       bool
+	profileControls bool
+	profile         []ProfileEntry
+	nextProfile     []ProfileEntry
 
 	// stacks
 
-	commandList    []*command
+	commandList    []command
 	rootList       []*Container
 	containerStack []*Container
 	clipStack      []image.Rectangle
 	idStack        []ID
 	layoutStack    []layout
+	treeCheckStack []treeCheck
+	tabStops       []tabStop
+	modalIDs       []ID
 
 	// retained state pools
 
-	containerPool [containerPoolSize]poolItem
-	containers    [containerPoolSize]Container
-	treeNodePool  [treeNodePoolSize]poolItem
+	containerPool []poolItem
+	containers    []Container
+	zOrder        []*Container
+	treeNodePool  []poolItem
+	stateLimit    int
+	pinned        map[ID]struct{}
+	onEvict       func(id ID)
+
+	treeNodeRequests map[ID]treeNodeRequest
+	treeCheckPending map[ID]struct{}
+	widgetStates     map[widgetKey]*widgetState
 
 	// input state
 
-	mousePos     image.Point
-	lastMousePos image.Point
-	mouseDelta   image.Point
-	scrollDelta  image.Point
-	mouseDown    int
-	mousePressed int
-	keyDown      int
-	keyPressed   int
-	textInput    []rune
+	mousePos      image.Point
+	lastMousePos  image.Point
+	mouseDelta    image.Point
+	scrollDelta   image.Point
+	mouseDown     int
+	mousePressed  int
+	mouseReleased int
+	doubleClicked bool
+	clickMoved    bool
+	lastClick     time.Duration
+	lastClickPos  image.Point
+	keyDown       int
+	keyPressed    int
+	keysHeld      []ebiten.Key
+	keysPressed   []ebiten.Key
+	keysReleased  []ebiten.Key
+	keyRepeatAt   map[ebiten.Key]time.Duration
+	inputState    *InputState
+	repeatAt      time.Duration
+	textInput     []rune
+	inputRunes    []rune
 }
@@ -0,0 +1,62 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 The Ebitengine Authors
+
+package microui
+
+import (
+	"image"
+	"math"
+	"strings"
+	"unsafe"
+)
+
+// NumberUnit is a number field showing value followed by unit, like "px",
+// "%" or "ms". The unit can be typed in the text input mode, and is ignored.
+func (c *Context) NumberUnit(value *float64, step float64, unit string) Response {
+	format := "%.2f " + strings.ReplaceAll(unit, "%", "%%")
+	return c.NumberEx(value, step, format, OptAlignCenter)
+}
+
+// Angle is a number field for an angle in radians, wrapped to [0, 2π). It is
+// shown in degrees or in radians, toggled with the button at its right.
+func (c *Context) Angle(value *float64) Response {
+	c.pushID(ptrToBytes(unsafe.Pointer(value)))
+	defer c.popID()
+	id := c.id([]byte("!angle"))
+	toggleID := c.id([]byte("!unit"))
+	radians := WidgetState[bool](c.Widget(), toggleID)
+
+	var res Response
+	c.LayoutColumn(func() {
+		degLabel, radLabel := c.tr("deg"), c.tr("rad")
+		label := degLabel
+		if *radians {
+			label = radLabel
+		}
+		c.SetLayoutRow([]int{-(max(textWidth(degLabel), textWidth(radLabel)) + c.Style.Padding*2 + c.Style.Spacing), -1}, 0)
+		if *radians {
+			res = c.number(value, id, 0.01, valueFormat{verb: "%.3f rad"}, OptAlignCenter)
+		} else {
+			deg := *value * 180 / math.Pi
+			res = c.number(&deg, id, 1, valueFormat{verb: "%.1f°"}, OptAlignCenter)
+			if (res & ResponseChange) != 0 {
+				*value = deg * math.Pi / 180
+			}
+		}
+		if (res & ResponseChange) != 0 {
+			*value = math.Mod(*value, 2*math.Pi)
+			if *value < 0 {
+				*value += 2 * math.Pi
+			}
+		}
+		c.Control(toggleID, 0, func(r image.Rectangle) Response {
+			if c.activated(toggleID) {
+				*radians = !*radians
+			}
+			c.drawControlFrame(toggleID, r, ColorButton, 0)
+			c.drawControlText(label, r, ColorText, OptAlignCenter)
+			return 0
+		})
+	})
+	return res
+}
@@ -0,0 +1,51 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 The Ebitengine Authors
+
+package microui
+
+import (
+	"image"
+	"unsafe"
+)
+
+// drawInvalid frames rect with the error color.
+func (c *Context) drawInvalid(rect image.Rectangle) {
+	c.drawBox(rect, c.Style.Colors[ColorError])
+	c.drawBox(rect.Inset(-1), c.Style.Colors[ColorError])
+}
+
+// validated records err as the validation error of the last control. If err
+// isn't nil, the control is framed with the error color and ResponseInvalid
+// is returned.
+func (c *Context) validated(err error) Response {
+	c.lastError = err
+	if err == nil {
+		return 0
+	}
+	c.drawInvalid(c.lastRect)
+	return ResponseInvalid
+}
+
+// ValidationError returns the error returned by the validator of the last
+// control with a validator, or nil if its value is valid. The message can be
+// shown with Tooltip while the control is hovered.
+func (c *Context) ValidationError() error {
+	return c.lastError
+}
+
+// TextBoxValidated is like TextBox, but validates the text with validate in
+// every frame. When validate returns an error, the text box is framed with
+// the error color, ResponseInvalid is reported and the error is returned by
+// ValidationError.
+func (c *Context) TextBoxValidated(buf *string, validate func(text string) error) Response {
+	res := c.textBox(buf, textBoxConfig{}, 0)
+	return res | c.validated(validate(*buf))
+}
+
+// NumberValidated is like Number, but validates the value with validate in
+// every frame, like TextBoxValidated.
+func (c *Context) NumberValidated(value *float64, step float64, validate func(v float64) error) Response {
+	id := c.id(ptrToBytes(unsafe.Pointer(value)))
+	res := c.number(value, id, step, valueFormat{verb: sliderFmt}, OptAlignCenter)
+	return res | c.validated(validate(*value))
+}
@@ -0,0 +1,127 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 The Ebitengine Authors
+
+package microui
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"unsafe"
+)
+
+// Vec2 is a row of number fields for the X and Y components of value, filling
+// the width of the next cell of the layout. A toggle at the end of the row
+// links the components so that they are all changed together.
+func (c *Context) Vec2(value *[2]float64) Response {
+	return c.vector(unsafe.Pointer(value), value[:], "XY", 0.1, sliderFmt, nil)
+}
+
+// Vec3 is a row of number fields for the X, Y and Z components of value.
+func (c *Context) Vec3(value *[3]float64) Response {
+	return c.vector(unsafe.Pointer(value), value[:], "XYZ", 0.1, sliderFmt, nil)
+}
+
+// Vec4 is a row of number fields for the X, Y, Z and W components of value.
+func (c *Context) Vec4(value *[4]float64) Response {
+	return c.vector(unsafe.Pointer(value), value[:], "XYZW", 0.1, sliderFmt, nil)
+}
+
+// ColorRGBA is a row of number fields for the R, G, B and A components of
+// value, followed by a swatch of the color.
+func (c *Context) ColorRGBA(value *color.RGBA) Response {
+	// the components are edited through floats, kept between frames so that
+	// drags finer than 1 add up
+	id := c.id(ptrToBytes(unsafe.Pointer(value)))
+	v := WidgetState[[4]float64](c.Widget(), id)
+	comps := [4]*uint8{&value.R, &value.G, &value.B, &value.A}
+	for i, comp := range comps {
+		if uint8(math.Round(v[i])) != *comp {
+			*v = [4]float64{float64(value.R), float64(value.G), float64(value.B), float64(value.A)}
+			break
+		}
+	}
+	swatch := func(r image.Rectangle) {
+		c.drawRect(r, *value)
+		c.drawBox(r, c.Style.Colors[ColorBorder])
+	}
+	res := c.vector(unsafe.Pointer(value), v[:], "RGBA", 1, "%.0f", swatch)
+	if (res & ResponseChange) != 0 {
+		for i, comp := range comps {
+			v[i] = clampF(v[i], 0, 255)
+			*comp = uint8(math.Round(v[i]))
+		}
+	}
+	return res
+}
+
+// vector lays out a number field per component of values on a row, each
+// after its label in labels, then a toggle linking the components so that
+// they are all changed together, and swatch if it is not nil.
+func (c *Context) vector(ptr unsafe.Pointer, values []float64, labels string, step float64, format string, swatch func(r image.Rectangle)) Response {
+	c.pushID(ptrToBytes(ptr))
+	defer c.popID()
+	linkID := c.id([]byte("!link"))
+	linked := WidgetState[bool](c.Widget(), linkID)
+
+	var res Response
+	c.LayoutColumn(func() {
+		n := len(values)
+		labelw := textWidth("W") + c.Style.Padding*2
+		linkw := c.Style.Size.Y + c.Style.Padding*2
+		extra := 1
+		if swatch != nil {
+			extra++
+		}
+		total := c.layout().body.Dx() - linkw*extra - c.Style.Spacing*(n*2+extra-1)
+		fieldw := max(total/n-labelw, 1)
+		widths := make([]int, 0, n*2+extra)
+		for range values {
+			widths = append(widths, labelw, fieldw)
+		}
+		widths = append(widths, -1)
+		if swatch != nil {
+			widths[len(widths)-1] = linkw
+			widths = append(widths, -1)
+		}
+		c.SetLayoutRow(widths, 0)
+
+		for i := range values {
+			c.LabelEx(labels[i:i+1], OptAlignCenter)
+			last := values[i]
+			r := c.number(&values[i], c.id([]byte{'!', labels[i]}), step, valueFormat{verb: format}, OptAlignCenter)
+			if (r&ResponseChange) != 0 && *linked {
+				// change all the components by the same amount
+				d := values[i] - last
+				for j := range values {
+					if j != i {
+						values[j] += d
+					}
+				}
+			}
+			res |= r
+		}
+
+		c.Control(linkID, 0, func(r image.Rectangle) Response {
+			if c.activated(linkID) {
+				*linked = !*linked
+			}
+			colorid := ColorButton
+			if *linked {
+				colorid = ColorButtonFocus
+			}
+			c.drawControlFrame(linkID, r, colorid, 0)
+			c.drawControlText("=", r, ColorText, OptAlignCenter)
+			return 0
+		})
+		c.Tooltip(c.tr("Change all the components together"))
+
+		if swatch != nil {
+			c.Control(0, 0, func(r image.Rectangle) Response {
+				swatch(r)
+				return 0
+			})
+		}
+	})
+	return res
+}
@@ -0,0 +1,57 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 The Ebitengine Authors
+
+package microui
+
+import (
+	"image"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Viewport is an area of a render target showing its own set of windows,
+// like the half of the screen of a player in split-screen.
+type Viewport struct {
+	// Bounds is the area of the render target showing the viewport. The
+	// cursor is only given to the windows of the viewport inside it.
+	Bounds image.Rectangle
+
+	// Scale, X and Y transform the windows of the viewport to the render
+	// target like SetTransform, and the cursor back to the windows. X and Y
+	// are in the coordinates of the render target, not of Bounds. Zero Scale
+	// means 1.
+	Scale float64
+	X, Y  float64
+}
+
+func (v Viewport) transform() transform {
+	return transform{scale: v.Scale, x: v.X, y: v.Y}
+}
+
+// SetViewport sets the viewport n, which must be positive. The windows whose
+// Container.Viewport is n are drawn by DrawViewport instead of Draw.
+func (c *Context) SetViewport(n int, v Viewport) {
+	expect(n > 0)
+	if c.viewports == nil {
+		c.viewports = map[int]Viewport{}
+	}
+	if c.viewports[n] != v {
+		c.redraw = true
+	}
+	c.viewports[n] = v
+}
+
+// DrawViewport draws the windows of the viewport n to dst, clipped to the
+// bounds of the viewport.
+func (c *Context) DrawViewport(n int, dst *ebiten.Image) {
+	v := c.viewports[n]
+	screen := dst.SubImage(v.Bounds).(*ebiten.Image)
+	r := renderer{screen: screen, target: screen, base: v.transform(), t: v.transform()}
+	// the root containers are sorted from back to front
+	for _, cnt := range c.rootList {
+		if cnt.Viewport == n && !cnt.Offscreen {
+			c.drawContainer(&r, cnt)
+		}
+	}
+	r.flush()
+}
@@ -0,0 +1,210 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 The Ebitengine Authors
+
+package microui
+
+import (
+	"image"
+	"image/color"
+	"reflect"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// WidgetContext gives access to the building blocks of the controls, to
+// write custom controls outside of the package. A typical control gets its
+// rectangle with LayoutNext, or through Context.Control, updates its state
+// with UpdateControl, then draws itself.
+type WidgetContext struct {
+	c *Context
+}
+
+// Widget returns the WidgetContext of the context.
+func (c *Context) Widget() WidgetContext {
+	return WidgetContext{c: c}
+}
+
+// ID returns the ID of data in the current ID scope, like the address of the
+// value of the control or its label.
+func (w WidgetContext) ID(data []byte) ID {
+	return w.c.id(data)
+}
+
+// PushID pushes the ID scope of data, for the controls of a compound control.
+// It must be balanced with PopID.
+func (w WidgetContext) PushID(data []byte) ID {
+	return w.c.pushID(data)
+}
+
+// PopID pops the ID scope pushed with PushID.
+func (w WidgetContext) PopID() {
+	w.c.popID()
+}
+
+// LayoutNext returns the rectangle of the next control in the current layout.
+func (w WidgetContext) LayoutNext() image.Rectangle {
+	return w.c.layoutNext()
+}
+
+// UpdateControl updates the hover and the focus of the control id covering
+// rect.
+func (w WidgetContext) UpdateControl(id ID, rect image.Rectangle, opt Option) {
+	w.c.updateControl(id, rect, opt)
+}
+
+// Hovered reports whether the control id is hovered.
+func (w WidgetContext) Hovered(id ID) bool {
+	return w.c.hover == id
+}
+
+// Focused reports whether the control id has the focus.
+func (w WidgetContext) Focused(id ID) bool {
+	return w.c.focus == id
+}
+
+// Activated reports whether the control id was clicked, or activated with
+// the keyboard in keyboard navigation mode.
+func (w WidgetContext) Activated(id ID) bool {
+	return w.c.activated(id)
+}
+
+// MousePos returns the position of the mouse.
+func (w WidgetContext) MousePos() image.Point {
+	return w.c.mousePos
+}
+
+// MouseDelta returns the move of the mouse since the last frame.
+func (w WidgetContext) MouseDelta() image.Point {
+	return w.c.mouseDelta
+}
+
+// MouseDown reports whether the left mouse button is held.
+func (w WidgetContext) MouseDown() bool {
+	return (w.c.mouseDown & mouseLeft) != 0
+}
+
+// TextInput returns the text typed in the frame if the control id has the
+// focus, or nil, for the controls editing text their own way.
+func (w WidgetContext) TextInput(id ID) []rune {
+	if w.c.focus != id {
+		return nil
+	}
+	return w.c.textInput
+}
+
+// PressedKeys returns the keys pressed in the frame, or repeated while they
+// are held, if the control id has the focus, or nil.
+func (w WidgetContext) PressedKeys(id ID) []ebiten.Key {
+	if w.c.focus != id {
+		return nil
+	}
+	return w.c.keysPressed
+}
+
+// ReleasedKeys returns the keys released in the frame if the control id has
+// the focus, or nil.
+func (w WidgetContext) ReleasedKeys(id ID) []ebiten.Key {
+	if w.c.focus != id {
+		return nil
+	}
+	return w.c.keysReleased
+}
+
+// DrawControlFrame draws the frame of the control id with the color colorid,
+// like ColorButton, which is lightened while the control is hovered or
+// focused.
+func (w WidgetContext) DrawControlFrame(id ID, rect image.Rectangle, colorid int, opt Option) {
+	w.c.drawControlFrame(id, rect, colorid, opt)
+}
+
+// DrawControlText draws the text of a control in rect with the color colorid
+// and the alignment of opt.
+func (w WidgetContext) DrawControlText(str string, rect image.Rectangle, colorid int, opt Option) {
+	w.c.drawControlText(str, rect, colorid, opt)
+}
+
+// DrawRect fills rect with clr.
+func (w WidgetContext) DrawRect(rect image.Rectangle, clr color.Color) {
+	w.c.drawRect(rect, clr)
+}
+
+// DrawBox draws the outline of rect with clr.
+func (w WidgetContext) DrawBox(rect image.Rectangle, clr color.Color) {
+	w.c.drawBox(rect, clr)
+}
+
+// DrawText draws str at pos with clr.
+func (w WidgetContext) DrawText(str string, pos image.Point, clr color.Color) {
+	w.c.drawText(str, pos, clr)
+}
+
+// PushClipRect clips the following drawings to rect, within the current clip
+// rectangle. It must be balanced with PopClipRect.
+func (w WidgetContext) PushClipRect(rect image.Rectangle) {
+	w.c.pushClipRect(rect)
+}
+
+// PopClipRect pops the clip rectangle pushed with PushClipRect.
+func (w WidgetContext) PopClipRect() {
+	w.c.popClipRect()
+}
+
+// widgetState is the state of a custom control.
+type widgetState struct {
+	value      any
+	lastUpdate int
+}
+
+// widgetKey is the key of the state of type typ of the control id.
+type widgetKey struct {
+	id  ID
+	typ reflect.Type
+}
+
+// WidgetState returns the state of type T retained for the control id, which
+// is the zero value the first time. A control can have states of several
+// types, each retained apart. Like the state of the containers and the tree
+// nodes, the states are kept in a pool: once the pool is full, the state of
+// the control the least recently built is evicted to make room for a new
+// one, unless it is pinned with SetPinned. The pool grows if the states of
+// all the controls built in the frame don't fit in it.
+func WidgetState[T any](w WidgetContext, id ID) *T {
+	c := w.c
+	if c.widgetStates == nil {
+		c.widgetStates = map[widgetKey]*widgetState{}
+	}
+	key := widgetKey{id: id, typ: reflect.TypeFor[T]()}
+	s, ok := c.widgetStates[key]
+	if !ok {
+		if len(c.widgetStates) >= c.stateLimit {
+			c.evictWidgetState()
+		}
+		s = &widgetState{value: new(T)}
+		c.widgetStates[key] = s
+	}
+	s.lastUpdate = c.tick
+	return s.value.(*T)
+}
+
+// evictWidgetState evicts the unpinned state the least recently built before
+// this frame, if any.
+func (c *Context) evictWidgetState() {
+	var evict widgetKey
+	f := c.tick
+	for key, s := range c.widgetStates {
+		if _, ok := c.pinned[key.id]; ok {
+			continue
+		}
+		if s.lastUpdate < f {
+			f = s.lastUpdate
+			evict = key
+		}
+	}
+	if f == c.tick {
+		return
+	}
+	delete(c.widgetStates, evict)
+	if c.onEvict != nil {
+		c.onEvict(evict.id)
+	}
+}
@@ -5,12 +5,34 @@ package microui
 
 import "image"
 
+func (c *Context) Label(text string) {
+	c.LabelEx(text, 0)
+}
+
 func (c *Context) Button(label string) Response {
 	return c.buttonEx(label, OptAlignCenter)
 }
 
+// ButtonEx is like Button, with options. With OptRepeat, ResponseSubmit is
+// reported repeatedly while the button is held, after the key repeat delay
+// and then at every key repeat interval.
+func (c *Context) ButtonEx(label string, opt Option) Response {
+	return c.buttonEx(label, opt)
+}
+
 func (c *Context) TextBox(buf *string) Response {
-	return c.textBoxEx(buf, 0)
+	return c.textBox(buf, textBoxConfig{}, 0)
+}
+
+func (c *Context) TextBoxEx(buf *string, opt Option) Response {
+	return c.textBox(buf, textBoxConfig{}, opt)
+}
+
+// TextBoxLimit is like TextBox, but accepts at most maxLen runes, or any
+// number if maxLen is 0, and only the runes for which filter returns true, or
+// any rune if filter is nil.
+func (c *Context) TextBoxLimit(buf *string, maxLen int, filter func(r rune) bool) Response {
+	return c.textBox(buf, textBoxConfig{maxLen: maxLen, filter: filter}, 0)
 }
 
 func (c *Context) Slider(value *float64, lo, hi float64) Response {
@@ -33,6 +55,20 @@ func (c *Context) Window(title string, rect image.Rectangle, f func(res Response
 	c.window(title, rect, 0, f)
 }
 
+func (c *Context) WindowEx(title string, rect image.Rectangle, opt Option, f func(res Response)) {
+	c.window(title, rect, opt, f)
+}
+
 func (c *Context) Panel(name string, f func()) {
 	c.panel(name, 0, f)
 }
+
+// PanelEx is like Panel, with options, like OptContentCenter to center the
+// content of the panel.
+func (c *Context) PanelEx(name string, opt Option, f func()) {
+	c.panel(name, opt, f)
+}
+
+func (c *Context) TreeNodeCheck(label string, state *TriState, f func(res Response)) Response {
+	return c.treeNodeCheck(label, state, 0, f)
+}
@@ -0,0 +1,161 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 The Ebitengine Authors
+
+package microui
+
+import (
+	"image"
+	"slices"
+)
+
+// Windows are tabbed together by dragging the title of a window onto the
+// title of another one: they share a frame, with a tab per window in the
+// title bar, and only the window of the selected tab is built. Dragging a tab
+// out of the title bar detaches its window.
+//
+// The first window of a group is its host, holding the other windows in its
+// tabs; the windows of the tabs point to it with tabHost.
+
+// tabGroupHost returns the host of the group of windows tabbed with cnt.
+func tabGroupHost(cnt *Container) *Container {
+	if cnt.tabHost != nil {
+		return cnt.tabHost
+	}
+	return cnt
+}
+
+// activeWindowTab returns the window of the selected tab of the group of cnt.
+func activeWindowTab(cnt *Container) *Container {
+	host := tabGroupHost(cnt)
+	if host.activeTab != nil {
+		return host.activeTab
+	}
+	return host
+}
+
+// windowTabs returns the windows tabbed with cnt, including it, in the order
+// of their tabs, after leaving the closed windows out of the group.
+func windowTabs(cnt *Container) []*Container {
+	for _, m := range append([]*Container{tabGroupHost(cnt)}, tabGroupHost(cnt).tabs...) {
+		if !m.Open {
+			detachWindowTab(m)
+		}
+	}
+	host := tabGroupHost(cnt)
+	return append([]*Container{host}, host.tabs...)
+}
+
+// detachWindowTab removes the window cnt from its group. When cnt is the
+// host, the window of the next tab becomes the host.
+func detachWindowTab(cnt *Container) {
+	if host := cnt.tabHost; host != nil {
+		host.tabs = slices.DeleteFunc(host.tabs, func(m *Container) bool {
+			return m == cnt
+		})
+		if host.activeTab == cnt {
+			host.activeTab = nil
+		}
+		cnt.tabHost = nil
+		return
+	}
+	if len(cnt.tabs) == 0 {
+		return
+	}
+	host := cnt.tabs[0]
+	host.tabHost = nil
+	host.tabs = cnt.tabs[1:]
+	for _, m := range host.tabs {
+		m.tabHost = host
+	}
+	host.activeTab = nil
+	if cnt.activeTab != host {
+		host.activeTab = cnt.activeTab
+	}
+	cnt.tabs, cnt.activeTab = nil, nil
+}
+
+// mergeWindowTabs adds the group of the window cnt to the group of the window
+// target, selecting the tab of cnt.
+func (c *Context) mergeWindowTabs(cnt, target *Container) {
+	host := tabGroupHost(target)
+	for _, m := range windowTabs(cnt) {
+		m.tabHost = host
+		m.tabs, m.activeTab = nil, nil
+		m.Rect = host.Rect
+		host.tabs = append(host.tabs, m)
+	}
+	host.activeTab = cnt
+	c.bringToFront(cnt)
+}
+
+// dragWindowTitle records that the window cnt is dragged by its title, to
+// tab it with the window whose title it is dropped on.
+func (c *Context) dragWindowTitle(cnt *Container) {
+	c.titleDrag = cnt
+	if c.mouseDelta != (image.Point{}) {
+		c.titleDragMoved = true
+	}
+}
+
+// dropWindowTitle tabs the window dragged by its title with the window whose
+// title is under the mouse, once it is released.
+func (c *Context) dropWindowTitle() {
+	cnt := c.titleDrag
+	if cnt == nil || (c.mouseDown&mouseLeft) != 0 {
+		return
+	}
+	moved := c.titleDragMoved
+	c.titleDrag, c.titleDragMoved = nil, false
+	if !moved {
+		return
+	}
+	var target *Container
+	for _, t := range c.rootList {
+		if tabGroupHost(t) == tabGroupHost(cnt) || !c.mousePos.In(t.titleRect) {
+			continue
+		}
+		if target == nil || behind(target, t) {
+			target = t
+		}
+	}
+	if target != nil {
+		c.mergeWindowTabs(cnt, target)
+	}
+}
+
+// windowTabBar lays out the tabs of the windows tabs in the title bar tr of
+// the window cnt, the window of the selected tab. The window is dragged by its
+// tabs if movable is true.
+func (c *Context) windowTabBar(cnt *Container, tabs []*Container, tr image.Rectangle, delta image.Point, movable bool) {
+	x := tr.Min.X
+	for _, m := range tabs {
+		r := image.Rect(x, tr.Min.Y, x+textWidth(m.name)+c.Style.Padding*2, tr.Max.Y)
+		x = r.Max.X
+		id := c.id([]byte("!tab" + m.name))
+		c.updateControl(id, r, 0)
+
+		if c.focus == id && c.mousePressed == mouseLeft && m != cnt {
+			tabGroupHost(m).activeTab = m
+			c.bringToFront(m)
+		}
+		if c.focus == id && c.mouseDown == mouseLeft && c.mousePressed == 0 {
+			if p := c.mousePos; p.Y < tr.Min.Y-tr.Dy() || p.Y >= tr.Max.Y+tr.Dy() {
+				// detach the window, which is then dragged by its title
+				detachWindowTab(m)
+				m.Rect = m.Rect.Add(p.Sub(image.Pt(m.Rect.Min.X+r.Dx()/2, m.Rect.Min.Y+tr.Dy()/2)))
+				c.bringToFront(m)
+				c.SetFocus(fnv1a(m.id, []byte("!title")))
+			} else if movable {
+				cnt.Rect = cnt.Rect.Add(delta)
+				c.dragWindowTitle(cnt)
+			}
+		}
+
+		if m == cnt {
+			c.drawFrame(r, ColorWindowBG)
+		} else if c.hover == id {
+			c.drawFrame(r, ColorButtonHover)
+		}
+		c.drawControlText(m.name, r, ColorTitleText, OptAlignCenter)
+	}
+}